@@ -0,0 +1,180 @@
+package worldpay
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"time"
+)
+
+const (
+	queryTransactionFound    = "150"
+	queryTransactionNotFound = "151"
+	queryTransactionPending  = "152"
+
+	// autoReversalTimeout bounds the follow-up requests, which run after the
+	// caller's context may already have expired.
+	autoReversalTimeout = time.Minute
+)
+
+// autoReversalQueryBackoff is the wait before querying again for a
+// transaction that was found but whose response is not yet available. It is
+// doubled after each query.
+var autoReversalQueryBackoff = 2 * time.Second
+
+// AutoReversalResult reports the outcome of reversing a Sale or
+// Authorization whose request timed out.
+type AutoReversalResult struct {
+	// Transaction is the *Sale or *Authorization that timed out.
+	Transaction interface{}
+	// Err is the timeout returned to the caller.
+	Err error
+	// Query is the queryTransaction response used to find the original
+	// transaction.
+	Query *LitleOnlineResponse
+	// Reversal is the authReversal or void response, or nil if the original
+	// transaction was not found or not approved.
+	Reversal *LitleOnlineResponse
+	// ReversalErr is set when the query or the reversal failed, including
+	// when the original transaction was found but its response was still
+	// not available when the auto-reversal gave up.
+	ReversalErr error
+}
+
+// SetAutoReversal enables reversing a Sale or Authorization that ends in a
+// transport timeout, since the cardholder may have been charged without the
+// caller knowing. The original transaction is looked up by Id with a
+// queryTransaction, repeated while its response is not yet available, and,
+// if it was approved, voided or reversed. The outcome is passed to fn from a
+// separate goroutine. Transactions without an Id cannot be looked up and are
+// reported with an error.
+func (c *Client) SetAutoReversal(fn func(result AutoReversalResult)) {
+	c.AutoReversal = fn
+}
+
+func (c *Client) shouldAutoReverse(payload interface{}, err error) bool {
+	if c.AutoReversal == nil {
+		return false
+	}
+
+	var transportErr *TransportError
+	if !errors.As(err, &transportErr) || !transportErr.Timeout() {
+		return false
+	}
+
+	switch payload.(type) {
+	case *Authorization, *Sale:
+		return true
+	}
+	return false
+}
+
+// autoReverse runs the follow-up requests with the values of the caller's
+// context, such as its currency, so that they are routed to the same merchant
+// as the original transaction, but without its deadline.
+func (c *Client) autoReverse(ctx context.Context, merchantId string, payload interface{}, err error) {
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), autoReversalTimeout)
+	defer cancel()
+
+	result := AutoReversalResult{
+		Transaction: payload,
+		Err:         err,
+	}
+	result.Query, result.Reversal, result.ReversalErr = c.reverseTransaction(ctx, merchantId, payload)
+
+	c.AutoReversal(result)
+}
+
+func (c *Client) reverseTransaction(ctx context.Context, merchantId string, payload interface{}) (*LitleOnlineResponse, *LitleOnlineResponse, error) {
+	var query *QueryTransaction
+
+	// The follow-up requests get Ids of their own, so that the gateway's
+	// duplicate detection cannot answer them with the original response.
+	switch p := payload.(type) {
+	case *Authorization:
+		query = &QueryTransaction{ReportGroup: p.ReportGroup, CustomerId: p.CustomerId, OrigId: p.Id, OrigActionType: "A"}
+	case *Sale:
+		query = &QueryTransaction{ReportGroup: p.ReportGroup, CustomerId: p.CustomerId, OrigId: p.Id, OrigActionType: "S"}
+	}
+	if query.OrigId == "" {
+		return nil, nil, errors.New("Unable to query a transaction without an Id")
+	}
+
+	queryRes, err := c.queryOriginal(ctx, merchantId, query)
+	if err != nil {
+		return queryRes, nil, err
+	}
+
+	found := queryRes.QueryTransactionResponse
+	if found.Response == queryTransactionNotFound {
+		return queryRes, nil, nil
+	}
+
+	for _, auth := range found.Results.AuthorizationResponses {
+		if auth.Approved() {
+			reversalRes, err := c.AuthReversal(ctx, merchantId, &AuthReversal{
+				Id:          newTransactionId(),
+				ReportGroup: auth.ReportGroup,
+				CustomerId:  auth.CustomerId,
+				LitleTxnId:  auth.LitleTxnId,
+			})
+			return queryRes, reversalRes, err
+		}
+	}
+	for _, sale := range found.Results.SaleResponses {
+		if sale.Approved() {
+			reversalRes, err := c.Void(ctx, merchantId, &Void{
+				Id:          newTransactionId(),
+				ReportGroup: sale.ReportGroup,
+				LitleTxnId:  sale.LitleTxnId,
+			})
+			return queryRes, reversalRes, err
+		}
+	}
+
+	return queryRes, nil, nil
+}
+
+// queryOriginal queries for the original transaction, each time with a new
+// Id, until the gateway reports whether it was found. While its response is
+// not yet available the query is repeated with backoff, until ctx is done.
+func (c *Client) queryOriginal(ctx context.Context, merchantId string, query *QueryTransaction) (*LitleOnlineResponse, error) {
+	backoff := autoReversalQueryBackoff
+
+	for {
+		// Each query is new, so that it is not answered with an earlier
+		// pending response.
+		query.Id = newTransactionId()
+		queryRes, err := c.QueryTransaction(ctx, merchantId, query)
+		if err != nil {
+			return queryRes, err
+		}
+
+		found := queryRes.QueryTransactionResponse
+		switch {
+		case found == nil:
+			return queryRes, fmt.Errorf("Unable to query transaction %s: no queryTransactionResponse", query.OrigId)
+		case found.Found(), found.Response == queryTransactionNotFound:
+			return queryRes, nil
+		case !found.Pending():
+			return queryRes, fmt.Errorf("Unable to query transaction %s: response %s %s", query.OrigId, found.Response, found.Message)
+		}
+
+		select {
+		case <-ctx.Done():
+			return queryRes, fmt.Errorf("Transaction %s was found but its response is not yet available: %w", query.OrigId, ctx.Err())
+		case <-time.After(backoff):
+		}
+		backoff *= 2
+	}
+}
+
+// newTransactionId returns a random Id within the 25 characters the schema
+// allows.
+func newTransactionId() string {
+	b := make([]byte, 12)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
@@ -0,0 +1,242 @@
+package worldpay
+
+import (
+	"context"
+	"encoding/xml"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAutoReversal(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+
+		switch {
+		case strings.Contains(string(body), "<sale "):
+			time.Sleep(200 * time.Millisecond)
+		case strings.Contains(string(body), "<queryTransaction "):
+			w.Write([]byte(`<litleOnlineResponse version="11.4" xmlns="http://www.litle.com/schema" response="0" message="Valid Format">` +
+				`<queryTransactionResponse id="1"><response>150</response><message>Original transaction found</message><matchCount>1</matchCount>` +
+				`<results_max10><saleResponse id="1"><litleTxnId>82924701437133501</litleTxnId><response>000</response><message>Approved</message></saleResponse></results_max10>` +
+				`</queryTransactionResponse></litleOnlineResponse>`))
+		case strings.Contains(string(body), "<void "):
+			assert.Contains(t, string(body), "<litleTxnId>82924701437133501</litleTxnId>")
+			w.Write([]byte(`<litleOnlineResponse version="11.4" xmlns="http://www.litle.com/schema" response="0" message="Valid Format">` +
+				`<voidResponse id="1"><litleTxnId>82924701437133502</litleTxnId><response>000</response><message>Approved</message></voidResponse>` +
+				`</litleOnlineResponse>`))
+		}
+	}))
+	defer server.Close()
+
+	results := make(chan AutoReversalResult, 1)
+
+	c, _ := NewClient(login, password, server.URL)
+	c.Client.Timeout = 50 * time.Millisecond
+	c.SetAutoReversal(func(result AutoReversalResult) {
+		results <- result
+	})
+
+	sale := &Sale{Id: "1", OrderId: "5234234", Amount: 40000}
+	_, err := c.Sale(context.Background(), merchantId, sale)
+	assert.IsType(t, &TransportError{}, err)
+
+	select {
+	case result := <-results:
+		assert.Equal(t, sale, result.Transaction)
+		assert.Nil(t, result.ReversalErr)
+		assert.Equal(t, "150", result.Query.QueryTransactionResponse.Response)
+		assert.Equal(t, "000", result.Reversal.VoidResponse.Response)
+	case <-time.After(5 * time.Second):
+		t.Fatal("auto reversal was not reported")
+	}
+}
+
+func TestAutoReversalPending(t *testing.T) {
+	backoff := autoReversalQueryBackoff
+	autoReversalQueryBackoff = time.Millisecond
+	defer func() { autoReversalQueryBackoff = backoff }()
+
+	var (
+		mu      sync.Mutex
+		queries int
+		ids     []string
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+
+		req := &LitleOnlineRequest{}
+		xml.Unmarshal(body, req)
+
+		if req.Authorization != nil {
+			mu.Lock()
+			ids = append(ids, req.Authorization.Id)
+			mu.Unlock()
+			time.Sleep(200 * time.Millisecond)
+			return
+		}
+
+		mu.Lock()
+		defer mu.Unlock()
+		switch {
+		case req.QueryTransaction != nil:
+			ids = append(ids, req.QueryTransaction.Id)
+			assert.Equal(t, "1", req.QueryTransaction.OrigId)
+			code, message := "152", "Original transaction found but response not yet available"
+			if queries++; queries == 3 {
+				code, message = "150", "Original transaction found"
+			}
+			w.Write([]byte(`<litleOnlineResponse version="11.4" xmlns="http://www.litle.com/schema" response="0" message="Valid Format">` +
+				`<queryTransactionResponse id="` + req.QueryTransaction.Id + `"><response>` + code + `</response><message>` + message + `</message><matchCount>1</matchCount>` +
+				`<results_max10><authorizationResponse id="1"><litleTxnId>82924701437133501</litleTxnId><response>000</response><message>Approved</message></authorizationResponse></results_max10>` +
+				`</queryTransactionResponse></litleOnlineResponse>`))
+		case req.AuthReversal != nil:
+			ids = append(ids, req.AuthReversal.Id)
+			w.Write([]byte(`<litleOnlineResponse version="11.4" xmlns="http://www.litle.com/schema" response="0" message="Valid Format">` +
+				`<authReversalResponse id="` + req.AuthReversal.Id + `"><litleTxnId>82924701437133502</litleTxnId><response>000</response><message>Approved</message></authReversalResponse>` +
+				`</litleOnlineResponse>`))
+		}
+	}))
+	defer server.Close()
+
+	results := make(chan AutoReversalResult, 1)
+
+	c, _ := NewClient(login, password, server.URL)
+	c.Client.Timeout = 50 * time.Millisecond
+	c.SetAutoReversal(func(result AutoReversalResult) {
+		results <- result
+	})
+
+	_, err := c.Authorization(context.Background(), merchantId, &Authorization{Id: "1", OrderId: "5234234", Amount: 40000})
+	assert.IsType(t, &TransportError{}, err)
+
+	select {
+	case result := <-results:
+		assert.Nil(t, result.ReversalErr)
+		assert.Equal(t, "150", result.Query.QueryTransactionResponse.Response)
+		assert.Equal(t, "000", result.Reversal.AuthReversalResponse.Response)
+	case <-time.After(5 * time.Second):
+		t.Fatal("auto reversal was not reported")
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	assert.Equal(t, 3, queries)
+	// The original, three queries and the reversal each have their own Id.
+	seen := make(map[string]bool)
+	for _, id := range ids {
+		assert.NotEmpty(t, id)
+		assert.False(t, seen[id], "duplicate Id %s", id)
+		seen[id] = true
+	}
+	assert.Len(t, ids, 5)
+}
+
+func TestAutoReversalQueryError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+
+		switch {
+		case strings.Contains(string(body), "<sale "):
+			time.Sleep(200 * time.Millisecond)
+		case strings.Contains(string(body), "<queryTransaction "):
+			w.Write([]byte(`<litleOnlineResponse version="11.4" xmlns="http://www.litle.com/schema" response="0" message="Valid Format">` +
+				`<queryTransactionUnavailableResponse id="2"><response>153</response><message>Query unavailable</message></queryTransactionUnavailableResponse>` +
+				`</litleOnlineResponse>`))
+		}
+	}))
+	defer server.Close()
+
+	results := make(chan AutoReversalResult, 1)
+
+	c, _ := NewClient(login, password, server.URL)
+	c.Client.Timeout = 50 * time.Millisecond
+	c.SetAutoReversal(func(result AutoReversalResult) {
+		results <- result
+	})
+
+	_, err := c.Sale(context.Background(), merchantId, &Sale{Id: "1", OrderId: "5234234", Amount: 40000})
+	assert.IsType(t, &TransportError{}, err)
+
+	// A query that cannot tell whether the sale went through is an error,
+	// not a silent success.
+	select {
+	case result := <-results:
+		assert.NotNil(t, result.ReversalErr)
+		assert.Nil(t, result.Reversal)
+	case <-time.After(5 * time.Second):
+		t.Fatal("auto reversal was not reported")
+	}
+}
+
+func TestAutoReversalCurrencyRouter(t *testing.T) {
+	var (
+		mu       sync.Mutex
+		requests []*LitleOnlineRequest
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+
+		req := &LitleOnlineRequest{}
+		xml.Unmarshal(body, req)
+
+		mu.Lock()
+		requests = append(requests, req)
+		mu.Unlock()
+
+		switch {
+		case req.Sale != nil:
+			time.Sleep(200 * time.Millisecond)
+		case req.QueryTransaction != nil:
+			w.Write([]byte(`<litleOnlineResponse version="11.4" xmlns="http://www.litle.com/schema" response="0" message="Valid Format">` +
+				`<queryTransactionResponse id="` + req.QueryTransaction.Id + `"><response>150</response><message>Original transaction found</message><matchCount>1</matchCount>` +
+				`<results_max10><saleResponse id="1"><litleTxnId>82924701437133501</litleTxnId><response>000</response><message>Approved</message></saleResponse></results_max10>` +
+				`</queryTransactionResponse></litleOnlineResponse>`))
+		case req.Void != nil:
+			w.Write([]byte(`<litleOnlineResponse version="11.4" xmlns="http://www.litle.com/schema" response="0" message="Valid Format">` +
+				`<voidResponse id="` + req.Void.Id + `"><litleTxnId>82924701437133502</litleTxnId><response>000</response><message>Approved</message></voidResponse>` +
+				`</litleOnlineResponse>`))
+		}
+	}))
+	defer server.Close()
+
+	results := make(chan AutoReversalResult, 1)
+
+	c, _ := NewClient(login, password, server.URL, WithMerchantRouter(CurrencyRouter{
+		"EUR": {MerchantId: "400", Login: "eur-login", Password: "eur-password"},
+	}))
+	c.Client.Timeout = 50 * time.Millisecond
+	c.SetAutoReversal(func(result AutoReversalResult) {
+		results <- result
+	})
+
+	ctx, cancel := context.WithCancel(ContextWithCurrency(context.Background(), "EUR"))
+	_, err := c.Sale(ctx, "", &Sale{Id: "1", OrderId: "5234234", Amount: 40000})
+	assert.IsType(t, &TransportError{}, err)
+	// The follow-ups outlive the caller's context.
+	cancel()
+
+	select {
+	case result := <-results:
+		assert.Nil(t, result.ReversalErr)
+		assert.Equal(t, "000", result.Reversal.VoidResponse.Response)
+	case <-time.After(5 * time.Second):
+		t.Fatal("auto reversal was not reported")
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	// The sale, the query and the void are all sent under the routed merchant.
+	if assert.Len(t, requests, 3) {
+		for _, req := range requests {
+			assert.Equal(t, "400", req.MerchantId)
+			assert.Equal(t, "eur-login", req.Authentication.User)
+		}
+	}
+}
@@ -0,0 +1,220 @@
+package worldpay
+
+import (
+	"bytes"
+	"context"
+	"encoding/xml"
+	"fmt"
+	"io"
+)
+
+type (
+	LitleRequest struct {
+		XMLName          xml.Name        `xml:"litleRequest"`
+		Version          string          `xml:"version,attr"`
+		XmlNamespace     string          `xml:"xmlns,attr"`
+		Id               string          `xml:"id,attr,omitempty"`
+		NumBatchRequests int             `xml:"numBatchRequests,attr"`
+		Authentication   Authentication  `xml:"authentication"`
+		BatchRequests    []*BatchRequest `xml:"batchRequest"`
+		RFRRequest       *RFRRequest     `xml:"RFRRequest"`
+	}
+
+	// RFRRequest asks for the response file of a previously submitted
+	// session to be sent again.
+	RFRRequest struct {
+		XMLName        xml.Name `xml:"RFRRequest"`
+		LitleSessionId string   `xml:"litleSessionId"`
+	}
+
+	BatchRequest struct {
+		XMLName                xml.Name      `xml:"batchRequest"`
+		Id                     string        `xml:"id,attr,omitempty"`
+		NumAuths               int           `xml:"numAuths,attr,omitempty"`
+		AuthAmount             int           `xml:"authAmount,attr,omitempty"`
+		NumAuthReversals       int           `xml:"numAuthReversals,attr,omitempty"`
+		AuthReversalAmount     int           `xml:"authReversalAmount,attr,omitempty"`
+		NumCaptures            int           `xml:"numCaptures,attr,omitempty"`
+		CaptureAmount          int           `xml:"captureAmount,attr,omitempty"`
+		NumCredits             int           `xml:"numCredits,attr,omitempty"`
+		CreditAmount           int           `xml:"creditAmount,attr,omitempty"`
+		NumForceCaptures       int           `xml:"numForceCaptures,attr,omitempty"`
+		ForceCaptureAmount     int           `xml:"forceCaptureAmount,attr,omitempty"`
+		NumSales               int           `xml:"numSales,attr,omitempty"`
+		SaleAmount             int           `xml:"saleAmount,attr,omitempty"`
+		NumCaptureGivenAuths   int           `xml:"numCaptureGivenAuths,attr,omitempty"`
+		CaptureGivenAuthAmount int           `xml:"captureGivenAuthAmount,attr,omitempty"`
+		NumEcheckSales         int           `xml:"numEcheckSales,attr,omitempty"`
+		EcheckSalesAmount      int           `xml:"echeckSalesAmount,attr,omitempty"`
+		NumEcheckCredit        int           `xml:"numEcheckCredit,attr,omitempty"`
+		EcheckCreditAmount     int           `xml:"echeckCreditAmount,attr,omitempty"`
+		NumTokenRegistrations  int           `xml:"numTokenRegistrations,attr,omitempty"`
+		NumUpdateSubscriptions int           `xml:"numUpdateSubscriptions,attr,omitempty"`
+		NumCancelSubscriptions int           `xml:"numCancelSubscriptions,attr,omitempty"`
+		NumCreatePlans         int           `xml:"numCreatePlans,attr,omitempty"`
+		NumUpdatePlans         int           `xml:"numUpdatePlans,attr,omitempty"`
+		MerchantId             string        `xml:"merchantId,attr"`
+		Transactions           []interface{} `xml:",any"`
+	}
+)
+
+func NewBatchRequest(id, merchantId string) *BatchRequest {
+	return &BatchRequest{
+		Id:         id,
+		MerchantId: merchantId,
+	}
+}
+
+// Add appends a transaction to the batch and updates the batch's per-type
+// count and amount totals. Online-only transactions, such as Void, are
+// rejected.
+func (b *BatchRequest) Add(payload interface{}) error {
+	switch p := payload.(type) {
+	case *Authorization:
+		b.NumAuths++
+		b.AuthAmount += p.Amount
+	case *AuthReversal:
+		b.NumAuthReversals++
+		if p.Amount != nil {
+			b.AuthReversalAmount += *p.Amount
+		}
+	case *CancelSubscription:
+		b.NumCancelSubscriptions++
+	case *Capture:
+		b.NumCaptures++
+		b.CaptureAmount += p.Amount
+	case *CaptureGivenAuth:
+		b.NumCaptureGivenAuths++
+		b.CaptureGivenAuthAmount += p.Amount
+	case *CreatePlan:
+		b.NumCreatePlans++
+	case *Credit:
+		b.NumCredits++
+		if p.Amount != nil {
+			b.CreditAmount += *p.Amount
+		}
+	case *EcheckCredit:
+		b.NumEcheckCredit++
+		b.EcheckCreditAmount += p.Amount
+	case *EcheckSale:
+		b.NumEcheckSales++
+		b.EcheckSalesAmount += p.Amount
+	case *ForceCapture:
+		b.NumForceCaptures++
+		b.ForceCaptureAmount += p.Amount
+	case *RegisterTokenRequest:
+		b.NumTokenRegistrations++
+	case *Sale:
+		b.NumSales++
+		b.SaleAmount += p.Amount
+	case *UpdatePlan:
+		b.NumUpdatePlans++
+	case *UpdateSubscription:
+		b.NumUpdateSubscriptions++
+	default:
+		return fmt.Errorf("Unsupported batch transaction %T", payload)
+	}
+
+	b.Transactions = append(b.Transactions, payload)
+	return nil
+}
+
+func (c *Client) NewLitleRequest(ctx context.Context, batches ...*BatchRequest) (*LitleRequest, error) {
+	creds, err := c.credentials(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return &LitleRequest{
+		Version:          string(c.schemaVersion()),
+		XmlNamespace:     c.schemaVersion().namespace(),
+		NumBatchRequests: len(batches),
+		Authentication: Authentication{
+			User:     creds.Login,
+			Password: creds.Password,
+		},
+		BatchRequests: batches,
+	}, nil
+}
+
+// NewRFRRequest builds a Request For Response for the session identified by
+// litleSessionId, as returned in LitleResponse.LitleSessionId.
+func (c *Client) NewRFRRequest(ctx context.Context, litleSessionId string) (*LitleRequest, error) {
+	request, err := c.NewLitleRequest(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	request.RFRRequest = &RFRRequest{
+		LitleSessionId: litleSessionId,
+	}
+	return request, nil
+}
+
+// RequestForResponse submits a Request For Response over t under the given
+// file name and passes every transaction response in the returned file to fn.
+func (c *Client) RequestForResponse(ctx context.Context, t BatchTransport, name, litleSessionId string, fn func(batch *BatchResponse, response interface{}) error) error {
+	request, err := c.NewRFRRequest(ctx, litleSessionId)
+	if err != nil {
+		return err
+	}
+
+	var buf bytes.Buffer
+	if _, err := request.WriteTo(&buf); err != nil {
+		return err
+	}
+	if err := t.Send(ctx, name, &buf); err != nil {
+		return err
+	}
+
+	f, err := t.Receive(ctx, name)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	return NewBatchResponseReader(f).Each(fn)
+}
+
+func (r *LitleRequest) AddBatch(batch *BatchRequest) {
+	r.BatchRequests = append(r.BatchRequests, batch)
+	r.NumBatchRequests = len(r.BatchRequests)
+}
+
+// WriteTo writes the request as an XML document ready to be submitted for
+// batch processing.
+func (r *LitleRequest) WriteTo(w io.Writer) (int64, error) {
+	cw := &countingWriter{w: w}
+
+	if _, err := io.WriteString(cw, xml.Header); err != nil {
+		return cw.n, err
+	}
+
+	if SchemaVersion(r.Version).cnp() {
+		data, err := MarshalSchema(r, SchemaVersion(r.Version))
+		if err != nil {
+			return cw.n, err
+		}
+		_, err = cw.Write(data)
+		return cw.n, err
+	}
+
+	enc := xml.NewEncoder(cw)
+	enc.Indent("", "  ")
+	if err := enc.Encode(r); err != nil {
+		return cw.n, err
+	}
+
+	return cw.n, nil
+}
+
+type countingWriter struct {
+	w io.Writer
+	n int64
+}
+
+func (cw *countingWriter) Write(p []byte) (int, error) {
+	n, err := cw.w.Write(p)
+	cw.n += int64(n)
+	return n, err
+}
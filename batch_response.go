@@ -0,0 +1,151 @@
+package worldpay
+
+import (
+	"encoding/xml"
+	"io"
+)
+
+type (
+	LitleResponse struct {
+		XMLName        xml.Name `xml:"litleResponse"`
+		Version        string   `xml:"version,attr"`
+		XmlNS          string   `xml:"xmlns,attr"`
+		Id             string   `xml:"id,attr"`
+		Response       string   `xml:"response,attr"`
+		Message        string   `xml:"message,attr"`
+		LitleSessionId string   `xml:"litleSessionId,attr"`
+	}
+
+	BatchResponse struct {
+		XMLName      xml.Name `xml:"batchResponse"`
+		Id           string   `xml:"id,attr"`
+		LitleBatchId string   `xml:"litleBatchId,attr"`
+		MerchantId   string   `xml:"merchantId,attr"`
+	}
+
+	// BatchResponseReader decodes a litleResponse or cnpResponse document
+	// one transaction response at a time, so large batch files never have to
+	// be held in memory.
+	BatchResponseReader struct {
+		dec      *xml.Decoder
+		response *LitleResponse
+		batch    *BatchResponse
+	}
+)
+
+var batchResponseTypes = map[string]func() interface{}{
+	"authorizationResponse":      func() interface{} { return &AuthorizationResponse{} },
+	"authReversalResponse":       func() interface{} { return &AuthReversalResponse{} },
+	"cancelSubscriptionResponse": func() interface{} { return &CancelSubscriptionResponse{} },
+	"captureResponse":            func() interface{} { return &CaptureResponse{} },
+	"captureGivenAuthResponse":   func() interface{} { return &CaptureGivenAuthResponse{} },
+	"createPlanResponse":         func() interface{} { return &CreatePlanResponse{} },
+	"creditResponse":             func() interface{} { return &CreditResponse{} },
+	"echeckCreditResponse":       func() interface{} { return &EcheckCreditResponse{} },
+	"echeckSalesResponse":        func() interface{} { return &EcheckSaleResponse{} },
+	"forceCaptureResponse":       func() interface{} { return &ForceCaptureResponse{} },
+	"registerTokenResponse":      func() interface{} { return &RegisterTokenResponse{} },
+	"saleResponse":               func() interface{} { return &SaleResponse{} },
+	"updatePlanResponse":         func() interface{} { return &UpdatePlanResponse{} },
+	"updateSubscriptionResponse": func() interface{} { return &UpdateSubscriptionResponse{} },
+}
+
+func NewBatchResponseReader(r io.Reader) *BatchResponseReader {
+	return &BatchResponseReader{
+		dec: NewSchemaDecoder(r),
+	}
+}
+
+// Response returns the litleResponse header, or nil if it has not been read
+// yet.
+func (r *BatchResponseReader) Response() *LitleResponse {
+	return r.response
+}
+
+// Batch returns the batchResponse that contains the most recently returned
+// transaction response.
+func (r *BatchResponseReader) Batch() *BatchResponse {
+	return r.batch
+}
+
+// Next returns the next transaction response, such as a *SaleResponse or
+// *CaptureResponse. It returns io.EOF once the document is exhausted.
+// Unrecognised elements are skipped.
+func (r *BatchResponseReader) Next() (interface{}, error) {
+	for {
+		tok, err := r.dec.Token()
+		if err != nil {
+			return nil, err
+		}
+
+		start, ok := tok.(xml.StartElement)
+		if !ok {
+			continue
+		}
+
+		switch start.Name.Local {
+		case "litleResponse":
+			r.response = &LitleResponse{
+				XMLName:        start.Name,
+				Version:        attrValue(start, "version"),
+				XmlNS:          start.Name.Space,
+				Id:             attrValue(start, "id"),
+				Response:       attrValue(start, "response"),
+				Message:        attrValue(start, "message"),
+				LitleSessionId: attrValue(start, "litleSessionId"),
+			}
+			if r.response.Response != "0" {
+				return nil, &SchemaError{Response: r.response.Response, Message: r.response.Message}
+			}
+		case "RFRResponse":
+			return nil, &SchemaError{Response: attrValue(start, "response"), Message: attrValue(start, "message")}
+		case "batchResponse":
+			r.batch = &BatchResponse{
+				XMLName:      start.Name,
+				Id:           attrValue(start, "id"),
+				LitleBatchId: attrValue(start, "litleBatchId"),
+				MerchantId:   attrValue(start, "merchantId"),
+			}
+		default:
+			newResponse, ok := batchResponseTypes[start.Name.Local]
+			if !ok {
+				if err := r.dec.Skip(); err != nil {
+					return nil, err
+				}
+				continue
+			}
+
+			v := newResponse()
+			if err := r.dec.DecodeElement(v, &start); err != nil {
+				return nil, err
+			}
+			return v, nil
+		}
+	}
+}
+
+// Each calls fn for every transaction response in the document, stopping at
+// the first error returned by fn.
+func (r *BatchResponseReader) Each(fn func(batch *BatchResponse, response interface{}) error) error {
+	for {
+		v, err := r.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if err := fn(r.batch, v); err != nil {
+			return err
+		}
+	}
+}
+
+func attrValue(start xml.StartElement, name string) string {
+	for _, a := range start.Attr {
+		if a.Name.Local == name {
+			return a.Value
+		}
+	}
+	return ""
+}
@@ -0,0 +1,97 @@
+package worldpay
+
+import (
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const batchResponseXml = `<?xml version="1.0" encoding="UTF-8"?>
+<litleResponse version="11.4" xmlns="http://www.litle.com/schema" response="0" message="Valid Format" litleSessionId="82822223274065939">
+  <batchResponse id="batch1" litleBatchId="82822223274065940" merchantId="100">
+    <saleResponse id="1" reportGroup="ABC Division">
+      <litleTxnId>82822223274065941</litleTxnId>
+      <orderId>5234234</orderId>
+      <response>000</response>
+      <responseTime>2023-06-01T10:00:00</responseTime>
+      <message>Approved</message>
+    </saleResponse>
+    <captureResponse id="2" reportGroup="ABC Division">
+      <litleTxnId>82822223274065942</litleTxnId>
+      <response>000</response>
+      <message>Approved</message>
+    </captureResponse>
+    <unknownResponse id="3"><response>000</response></unknownResponse>
+    <creditResponse id="4" reportGroup="ABC Division">
+      <litleTxnId>82822223274065943</litleTxnId>
+      <response>360</response>
+      <message>No transaction found with specified litleTxnId</message>
+    </creditResponse>
+  </batchResponse>
+</litleResponse>`
+
+func TestBatchResponseReaderNext(t *testing.T) {
+	r := NewBatchResponseReader(strings.NewReader(batchResponseXml))
+
+	v, err := r.Next()
+	assert.Nil(t, err)
+	sale, ok := v.(*SaleResponse)
+	assert.True(t, ok)
+	assert.Equal(t, "82822223274065941", sale.LitleTxnId)
+	assert.Equal(t, "000", sale.Response)
+	assert.Equal(t, "82822223274065939", r.Response().LitleSessionId)
+	assert.Equal(t, "82822223274065940", r.Batch().LitleBatchId)
+
+	v, err = r.Next()
+	assert.Nil(t, err)
+	assert.IsType(t, &CaptureResponse{}, v)
+
+	v, err = r.Next()
+	assert.Nil(t, err)
+	assert.Equal(t, "360", v.(*CreditResponse).Response)
+
+	_, err = r.Next()
+	assert.Equal(t, io.EOF, err)
+}
+
+func TestBatchResponseReaderEach(t *testing.T) {
+	var ids []string
+
+	r := NewBatchResponseReader(strings.NewReader(batchResponseXml))
+	err := r.Each(func(batch *BatchResponse, response interface{}) error {
+		assert.Equal(t, "batch1", batch.Id)
+		switch res := response.(type) {
+		case *SaleResponse:
+			ids = append(ids, res.Id)
+		case *CaptureResponse:
+			ids = append(ids, res.Id)
+		case *CreditResponse:
+			ids = append(ids, res.Id)
+		}
+		return nil
+	})
+
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"1", "2", "4"}, ids)
+}
+
+func TestBatchResponseReaderError(t *testing.T) {
+	r := NewBatchResponseReader(strings.NewReader(
+		`<litleResponse version="11.4" xmlns="http://www.litle.com/schema" response="1" message="Error validating xml data against the schema"></litleResponse>`,
+	))
+
+	_, err := r.Next()
+	assert.NotNil(t, err)
+	assert.Equal(t, "1", r.Response().Response)
+}
+
+func TestBatchResponseReaderRFRError(t *testing.T) {
+	r := NewBatchResponseReader(strings.NewReader(
+		`<litleResponse version="11.4" xmlns="http://www.litle.com/schema" response="0" message="Valid Format"><RFRResponse response="1" message="The requested response file is not yet available"/></litleResponse>`,
+	))
+
+	_, err := r.Next()
+	assert.NotNil(t, err)
+}
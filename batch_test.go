@@ -0,0 +1,108 @@
+package worldpay
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBatchRequestAdd(t *testing.T) {
+	amount := 1500
+	batch := NewBatchRequest("batch1", merchantId)
+
+	assert.Nil(t, batch.Add(&Sale{Id: "1", OrderId: "1", Amount: 1000, OrderSource: "ecommerce"}))
+	assert.Nil(t, batch.Add(&Sale{Id: "2", OrderId: "2", Amount: 2000, OrderSource: "ecommerce"}))
+	assert.Nil(t, batch.Add(&Capture{Id: "3", LitleTxnId: "13254123434", Amount: 5000}))
+	assert.Nil(t, batch.Add(&Credit{Id: "4", LitleTxnId: "13254123434", Amount: &amount}))
+	assert.Nil(t, batch.Add(&Credit{Id: "5", LitleTxnId: "13254123435"}))
+	assert.NotNil(t, batch.Add(&Void{Id: "6", LitleTxnId: "13254123434"}))
+
+	assert.Equal(t, 2, batch.NumSales)
+	assert.Equal(t, 3000, batch.SaleAmount)
+	assert.Equal(t, 1, batch.NumCaptures)
+	assert.Equal(t, 5000, batch.CaptureAmount)
+	assert.Equal(t, 2, batch.NumCredits)
+	assert.Equal(t, 1500, batch.CreditAmount)
+	assert.Len(t, batch.Transactions, 5)
+}
+
+func TestLitleRequestWriteTo(t *testing.T) {
+	batch := NewBatchRequest("batch1", merchantId)
+	batch.Add(&Capture{Id: "1", LitleTxnId: "13254123434", Amount: 5000})
+	batch.Add(&EcheckCredit{Id: "2", LitleTxnId: "4455667788", Amount: 1000})
+
+	c, _ := NewClient(login, password, apiBase)
+	req, _ := c.NewLitleRequest(context.Background(), batch)
+
+	var buf bytes.Buffer
+	n, err := req.WriteTo(&buf)
+	out := buf.String()
+
+	assert.Nil(t, err)
+	assert.Equal(t, int64(buf.Len()), n)
+	assert.Contains(t, out, `<litleRequest version="11.4" xmlns="http://www.litle.com/schema" numBatchRequests="1">`)
+	assert.Contains(t, out, `<batchRequest id="batch1" numCaptures="1" captureAmount="5000" numEcheckCredit="1" echeckCreditAmount="1000" merchantId="100">`)
+	assert.Contains(t, out, `<capture id="1"`)
+	assert.Contains(t, out, `<echeckCredit id="2"`)
+}
+
+func TestNewRFRRequest(t *testing.T) {
+	c, _ := NewClient(login, password, apiBase)
+
+	var buf bytes.Buffer
+	req, _ := c.NewRFRRequest(context.Background(), "82822223274065939")
+	_, err := req.WriteTo(&buf)
+	out := buf.String()
+
+	assert.Nil(t, err)
+	assert.Contains(t, out, `numBatchRequests="0"`)
+	assert.Contains(t, out, "<RFRRequest>")
+	assert.Contains(t, out, "<litleSessionId>82822223274065939</litleSessionId>")
+}
+
+func TestRequestForResponse(t *testing.T) {
+	inbound, outbound := t.TempDir(), t.TempDir()
+	transport := NewDirTransport(inbound, outbound)
+	transport.PollInterval = 10 * time.Millisecond
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	// Stand-in server answering the RFR with the original batch response.
+	// It stops once the test returns, even if the request never arrives.
+	done := make(chan struct{})
+	t.Cleanup(func() { close(done) })
+	go func() {
+		for {
+			req, err := os.ReadFile(filepath.Join(inbound, "rfr1.asc"))
+			if err == nil && strings.Contains(string(req), "82822223274065939") {
+				writeBatchFile(filepath.Join(outbound, "rfr1"), batchResponseXml)
+				return
+			}
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-done:
+				return
+			case <-time.After(10 * time.Millisecond):
+			}
+		}
+	}()
+
+	var count int
+	c, _ := NewClient(login, password, apiBase)
+	err := c.RequestForResponse(ctx, transport, "rfr1", "82822223274065939", func(batch *BatchResponse, response interface{}) error {
+		count++
+		return nil
+	})
+
+	assert.Nil(t, err)
+	assert.Equal(t, 3, count)
+}
@@ -0,0 +1,230 @@
+package worldpay
+
+import (
+	"context"
+	"errors"
+	"io"
+	"io/fs"
+	"net"
+	"os"
+	"path"
+	"path/filepath"
+	"time"
+
+	"github.com/pkg/sftp"
+	"golang.org/x/crypto/ssh"
+)
+
+const (
+	defaultPollInterval = 30 * time.Second
+	defaultInboundDir   = "inbound"
+	defaultOutboundDir  = "outbound"
+)
+
+type (
+	// BatchTransport delivers batch request files to Worldpay and picks up
+	// the matching response files.
+	BatchTransport interface {
+		// Send uploads a batch request file under the given name.
+		Send(ctx context.Context, name string, r io.Reader) error
+		// Receive waits until the response file for name is available and
+		// returns it. The caller must close the returned reader.
+		Receive(ctx context.Context, name string) (io.ReadCloser, error)
+	}
+
+	// SFTPTransport exchanges batch files over sFTP. Requests are uploaded
+	// to InboundDir as .prg files and renamed to .asc once complete, and
+	// responses are polled for in OutboundDir.
+	SFTPTransport struct {
+		Addr         string
+		Config       *ssh.ClientConfig
+		InboundDir   string
+		OutboundDir  string
+		PollInterval time.Duration
+	}
+
+	// DirTransport exchanges batch files through local directories, using
+	// the same naming scheme as SFTPTransport. It is intended for use with a
+	// stand-in server during testing. As with the sFTP server, a response
+	// is read as soon as its .asc file exists, so the stand-in must write
+	// it under another name and rename it into place once complete.
+	DirTransport struct {
+		InboundDir   string
+		OutboundDir  string
+		PollInterval time.Duration
+	}
+
+	// batchFS is the subset of file operations the transports need.
+	batchFS interface {
+		Create(name string) (io.WriteCloser, error)
+		Open(name string) (io.ReadCloser, error)
+		Rename(oldname, newname string) error
+	}
+
+	sftpFS struct {
+		client *sftp.Client
+	}
+
+	dirFS struct{}
+
+	sftpReadCloser struct {
+		io.ReadCloser
+		client *sftp.Client
+		conn   *ssh.Client
+	}
+)
+
+func NewSFTPTransport(addr, user, password string, hostKeyCallback ssh.HostKeyCallback) *SFTPTransport {
+	return &SFTPTransport{
+		Addr: addr,
+		Config: &ssh.ClientConfig{
+			User:            user,
+			Auth:            []ssh.AuthMethod{ssh.Password(password)},
+			HostKeyCallback: hostKeyCallback,
+		},
+		InboundDir:   defaultInboundDir,
+		OutboundDir:  defaultOutboundDir,
+		PollInterval: defaultPollInterval,
+	}
+}
+
+func (t *SFTPTransport) Send(ctx context.Context, name string, r io.Reader) error {
+	conn, client, err := t.dial(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	defer client.Close()
+
+	return sendBatchFile(&sftpFS{client}, path.Join(t.InboundDir, name), r)
+}
+
+func (t *SFTPTransport) Receive(ctx context.Context, name string) (io.ReadCloser, error) {
+	conn, client, err := t.dial(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	f, err := receiveBatchFile(ctx, &sftpFS{client}, path.Join(t.OutboundDir, name), t.PollInterval)
+	if err != nil {
+		client.Close()
+		conn.Close()
+		return nil, err
+	}
+
+	return &sftpReadCloser{ReadCloser: f, client: client, conn: conn}, nil
+}
+
+func (t *SFTPTransport) dial(ctx context.Context) (*ssh.Client, *sftp.Client, error) {
+	var d net.Dialer
+	netConn, err := d.DialContext(ctx, "tcp", t.Addr)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	sshConn, chans, reqs, err := ssh.NewClientConn(netConn, t.Addr, t.Config)
+	if err != nil {
+		netConn.Close()
+		return nil, nil, err
+	}
+	conn := ssh.NewClient(sshConn, chans, reqs)
+
+	client, err := sftp.NewClient(conn)
+	if err != nil {
+		conn.Close()
+		return nil, nil, err
+	}
+
+	return conn, client, nil
+}
+
+func NewDirTransport(inboundDir, outboundDir string) *DirTransport {
+	return &DirTransport{
+		InboundDir:   inboundDir,
+		OutboundDir:  outboundDir,
+		PollInterval: time.Second,
+	}
+}
+
+func (t *DirTransport) Send(ctx context.Context, name string, r io.Reader) error {
+	return sendBatchFile(dirFS{}, filepath.Join(t.InboundDir, name), r)
+}
+
+func (t *DirTransport) Receive(ctx context.Context, name string) (io.ReadCloser, error) {
+	return receiveBatchFile(ctx, dirFS{}, filepath.Join(t.OutboundDir, name), t.PollInterval)
+}
+
+// sendBatchFile writes r to name.prg and renames it to name.asc once the
+// upload is complete, so that a partial file is never picked up.
+func sendBatchFile(fsys batchFS, name string, r io.Reader) error {
+	prg := name + ".prg"
+
+	f, err := fsys.Create(prg)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(f, r); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+
+	return fsys.Rename(prg, name+".asc")
+}
+
+// receiveBatchFile polls for name.asc until it exists or ctx is done. The
+// file must be complete once it exists, i.e. it must be renamed into place.
+func receiveBatchFile(ctx context.Context, fsys batchFS, name string, interval time.Duration) (io.ReadCloser, error) {
+	if interval <= 0 {
+		interval = defaultPollInterval
+	}
+
+	for {
+		f, err := fsys.Open(name + ".asc")
+		if err == nil {
+			return f, nil
+		}
+		if !errors.Is(err, fs.ErrNotExist) {
+			return nil, err
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(interval):
+		}
+	}
+}
+
+func (s *sftpFS) Create(name string) (io.WriteCloser, error) {
+	return s.client.Create(name)
+}
+
+func (s *sftpFS) Open(name string) (io.ReadCloser, error) {
+	return s.client.Open(name)
+}
+
+func (s *sftpFS) Rename(oldname, newname string) error {
+	return s.client.Rename(oldname, newname)
+}
+
+func (dirFS) Create(name string) (io.WriteCloser, error) {
+	return os.Create(name)
+}
+
+func (dirFS) Open(name string) (io.ReadCloser, error) {
+	return os.Open(name)
+}
+
+func (dirFS) Rename(oldname, newname string) error {
+	return os.Rename(oldname, newname)
+}
+
+func (r *sftpReadCloser) Close() error {
+	err := r.ReadCloser.Close()
+	r.client.Close()
+	r.conn.Close()
+	return err
+}
@@ -0,0 +1,83 @@
+package worldpay
+
+import (
+	"context"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pkg/sftp"
+	"github.com/stretchr/testify/assert"
+)
+
+// writeBatchFile writes a response file the way Worldpay does, renaming it
+// to name.asc once it is complete.
+func writeBatchFile(name, data string) error {
+	if err := os.WriteFile(name+".prg", []byte(data), 0o644); err != nil {
+		return err
+	}
+	return os.Rename(name+".prg", name+".asc")
+}
+
+func TestDirTransport(t *testing.T) {
+	inbound, outbound := t.TempDir(), t.TempDir()
+	transport := NewDirTransport(inbound, outbound)
+	transport.PollInterval = 10 * time.Millisecond
+
+	err := transport.Send(context.Background(), "batch1", strings.NewReader("<litleRequest/>"))
+	assert.Nil(t, err)
+
+	_, err = os.Stat(filepath.Join(inbound, "batch1.prg"))
+	assert.True(t, os.IsNotExist(err))
+	sent, _ := os.ReadFile(filepath.Join(inbound, "batch1.asc"))
+	assert.Equal(t, "<litleRequest/>", string(sent))
+
+	go func() {
+		time.Sleep(50 * time.Millisecond)
+		writeBatchFile(filepath.Join(outbound, "batch1"), "<litleResponse/>")
+	}()
+
+	f, err := transport.Receive(context.Background(), "batch1")
+	assert.Nil(t, err)
+	received, _ := io.ReadAll(f)
+	f.Close()
+	assert.Equal(t, "<litleResponse/>", string(received))
+}
+
+func TestDirTransportReceiveTimeout(t *testing.T) {
+	transport := NewDirTransport(t.TempDir(), t.TempDir())
+	transport.PollInterval = 10 * time.Millisecond
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	_, err := transport.Receive(ctx, "batch1")
+	assert.Equal(t, context.DeadlineExceeded, err)
+}
+
+func TestSftpFS(t *testing.T) {
+	clientConn, serverConn := net.Pipe()
+	server := sftp.NewRequestServer(serverConn, sftp.InMemHandler())
+	go server.Serve()
+	defer server.Close()
+
+	client, err := sftp.NewClientPipe(clientConn, clientConn)
+	assert.Nil(t, err)
+	defer client.Close()
+
+	fsys := &sftpFS{client}
+	assert.Nil(t, sendBatchFile(fsys, "/batch1", strings.NewReader("<litleRequest/>")))
+
+	_, err = client.Stat("/batch1.prg")
+	assert.True(t, os.IsNotExist(err))
+
+	f, err := receiveBatchFile(context.Background(), fsys, "/batch1", time.Millisecond)
+	assert.Nil(t, err)
+	received, _ := io.ReadAll(f)
+	f.Close()
+	assert.Equal(t, "<litleRequest/>", string(received))
+}
@@ -3,7 +3,6 @@ package worldpay
 import (
 	"bytes"
 	"context"
-	"encoding/xml"
 	"errors"
 	"fmt"
 	"io"
@@ -12,25 +11,45 @@ import (
 	"net/http/httputil"
 )
 
-const (
-	version      = "11.4"
-	xmlNamespace = "http://www.litle.com/schema"
-)
-
-func NewClient(login, password, apiBase string) (*Client, error) {
-	if login == "" || password == "" || apiBase == "" {
-		return nil, errors.New("Missing required credentials")
-	}
-
-	return &Client{
+func NewClient(login, password, apiBase string, opts ...Option) (*Client, error) {
+	c := &Client{
 		Client:   &http.Client{},
 		Login:    login,
 		Password: password,
 		ApiBase:  apiBase,
-	}, nil
+	}
+
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	if (c.credentialsProvider == nil && (c.Login == "" || c.Password == "")) || c.ApiBase == "" {
+		return nil, errors.New("Missing required credentials")
+	}
+
+	if c.Client == nil {
+		c.Client = &http.Client{}
+	}
+
+	if c.timeout > 0 {
+		// Copy the HTTP client rather than changing one supplied by the
+		// caller, which may be shared.
+		httpClient := *c.Client
+		httpClient.Timeout = c.timeout
+		c.Client = &httpClient
+	}
+
+	return c, nil
 }
 
 func (c *Client) Send(req *http.Request, v interface{}) error {
+	_, err := c.send(req, v)
+	return err
+}
+
+// send is Send, also returning the HTTP status code of the response, or 0 if
+// none was received.
+func (c *Client) send(req *http.Request, v interface{}) (int, error) {
 	var (
 		err  error
 		resp *http.Response
@@ -38,9 +57,15 @@ func (c *Client) Send(req *http.Request, v interface{}) error {
 
 	// default headers
 	req.Header.Set("Content-Type", "text/xml")
+	if c.UserAgent != "" {
+		req.Header.Set("User-Agent", c.UserAgent)
+	}
 
 	// Read the request body
-	reqBody, _ := ioutil.ReadAll(req.Body)
+	reqBody, err := ioutil.ReadAll(req.Body)
+	if err != nil {
+		return 0, err
+	}
 	// Create a new buffer with the request body content
 	bodyBuffer := bytes.NewBuffer(reqBody)
 	// Reset the request body for the subsequent request
@@ -50,12 +75,33 @@ func (c *Client) Send(req *http.Request, v interface{}) error {
 	c.log(req, reqBody, resp)
 
 	if err != nil {
-		return err
+		return 0, &TransportError{Err: err}
 	}
 
 	defer resp.Body.Close()
 
-	return xml.NewDecoder(resp.Body).Decode(v)
+	respBody, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return resp.StatusCode, &TransportError{Err: err}
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return resp.StatusCode, &HTTPStatusError{
+			StatusCode: resp.StatusCode,
+			Status:     resp.Status,
+			Body:       bodySnippet(respBody),
+		}
+	}
+
+	if err := NewSchemaDecoder(bytes.NewReader(respBody)).Decode(v); err != nil {
+		return resp.StatusCode, &DecodeError{Err: err, Body: bodySnippet(respBody)}
+	}
+
+	if r, ok := v.(*LitleOnlineResponse); ok && r.HasError() {
+		return resp.StatusCode, &SchemaError{Response: r.Response, Message: r.Message}
+	}
+
+	return resp.StatusCode, nil
 }
 
 func (c *Client) SetLog(log io.Writer) {
@@ -69,11 +115,18 @@ func (c *Client) log(r *http.Request, reqBody []byte, resp *http.Response) {
 			respDump []byte
 		)
 
+		if !c.LogUnmasked {
+			reqBody = MaskSensitive(reqBody)
+		}
+
 		if r != nil {
 			reqDump = fmt.Sprintf("%s %s \n\n%s", r.Method, r.URL.String(), string(reqBody))
 		}
 		if resp != nil {
 			respDump, _ = httputil.DumpResponse(resp, true)
+			if !c.LogUnmasked {
+				respDump = MaskSensitive(respDump)
+			}
 		}
 
 		c.Log.Write([]byte(fmt.Sprintf("Request: %s\n\n\nResponse: %s\n", reqDump, string(respDump))))
@@ -81,13 +134,39 @@ func (c *Client) log(r *http.Request, reqBody []byte, resp *http.Response) {
 }
 
 func (c *Client) GetTransactionXml(merchantId string, payload interface{}) ([]byte, error) {
+	return c.getTransactionXml(context.Background(), merchantId, payload)
+}
+
+func (c *Client) getTransactionXml(ctx context.Context, merchantId string, payload interface{}) ([]byte, error) {
+	data, _, err := c.transactionXml(ctx, merchantId, payload)
+	return data, err
+}
+
+// transactionXml returns the online request for payload, along with the
+// merchantId it was resolved to.
+func (c *Client) transactionXml(ctx context.Context, merchantId string, payload interface{}) ([]byte, string, error) {
+	if v, ok := payload.(validatable); ok && c.ValidateRequests {
+		if err := v.Validate(); err != nil {
+			return nil, "", err
+		}
+	}
+	if err := paymentSourceConflict(payload); err != nil {
+		return nil, "", err
+	}
+
+	merchant, err := c.merchant(ctx, merchantId, payload)
+	if err != nil {
+		return nil, "", err
+	}
+
+	schema := c.schemaVersion()
 	request := LitleOnlineRequest{
-		Version:      version,
-		XmlNamespace: xmlNamespace,
-		MerchantId:   merchantId,
+		Version:      string(schema),
+		XmlNamespace: schema.namespace(),
+		MerchantId:   merchant.MerchantId,
 		Authentication: Authentication{
-			User:     c.Login,
-			Password: c.Password,
+			User:     merchant.Login,
+			Password: merchant.Password,
 		},
 	}
 
@@ -95,8 +174,16 @@ func (c *Client) GetTransactionXml(merchantId string, payload interface{}) ([]by
 	switch p := payload.(type) {
 	case *Authorization:
 		request.Authorization = p
+	case *AuthReversal:
+		request.AuthReversal = p
+	case *CancelSubscription:
+		request.CancelSubscription = p
 	case *Capture:
 		request.Capture = p
+	case *CaptureGivenAuth:
+		request.CaptureGivenAuth = p
+	case *CreatePlan:
+		request.CreatePlan = p
 	case *Credit:
 		request.Credit = p
 	case *EcheckCredit:
@@ -105,20 +192,36 @@ func (c *Client) GetTransactionXml(merchantId string, payload interface{}) ([]by
 		request.EcheckSale = p
 	case *EcheckVoid:
 		request.EcheckVoid = p
+	case *ForceCapture:
+		request.ForceCapture = p
+	case *QueryTransaction:
+		request.QueryTransaction = p
+	case *RegisterTokenRequest:
+		request.RegisterToken = p
 	case *Sale:
 		request.Sale = p
+	case *UpdatePlan:
+		request.UpdatePlan = p
+	case *UpdateSubscription:
+		request.UpdateSubscription = p
 	case *Void:
 		request.Void = p
+	default:
+		return nil, "", fmt.Errorf("Unsupported transaction %T", payload)
 	}
 
-	return xml.MarshalIndent(request, "", "  ")
+	data, err := MarshalSchema(request, schema)
+	return data, merchant.MerchantId, err
 }
 
 func (c *Client) NewRequest(ctx context.Context, merchantId string, payload interface{}) (*http.Request, error) {
-	xmlData, _ := c.GetTransactionXml(merchantId, payload)
+	xmlData, merchantId, err := c.transactionXml(ctx, merchantId, payload)
+	if err != nil {
+		return nil, err
+	}
 
 	return http.NewRequestWithContext(
-		ctx,
+		context.WithValue(ctx, merchantIdKey{}, merchantId),
 		http.MethodPost,
 		c.ApiBase,
 		bytes.NewReader(xmlData),
@@ -1,6 +1,10 @@
 package worldpay
 
 import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -46,3 +50,163 @@ func TestGetTransactionXmlPresence(t *testing.T) {
 
 	assert.NotNil(t, res)
 }
+
+func TestGetTransactionXmlToken(t *testing.T) {
+	sale := &Sale{
+		Id:          "1",
+		ReportGroup: "ABC Division",
+		OrderId:     "5234234",
+		Amount:      40000,
+		OrderSource: "ecommerce",
+		Token: &Token{
+			LitleToken:        "1111222233334444",
+			ExpDate:           "1210",
+			CardValidationNum: "555",
+		},
+	}
+
+	c, _ := NewClient(login, password, apiBase)
+	res, err := c.GetTransactionXml(merchantId, sale)
+
+	assert.Nil(t, err)
+	assert.NotContains(t, string(res), "<card>")
+	assert.Contains(t, string(res), "<litleToken>1111222233334444</litleToken>")
+}
+
+func TestGetTransactionXmlPaypage(t *testing.T) {
+	auth := &Authorization{
+		Id:          "1",
+		ReportGroup: "ABC Division",
+		OrderId:     "5234234",
+		Amount:      40000,
+		OrderSource: "ecommerce",
+		Paypage: &Paypage{
+			PaypageRegistrationId: "cDZJcmd1VjNlYXNaSlRMTGpocVZQY1NWVXE4Z W5UTko4NU9KK3p1L1p1Vzg4YzVPQVlSUHNITG1JN2I0NzlyTg=",
+			ExpDate:               "1210",
+		},
+	}
+
+	c, _ := NewClient(login, password, apiBase)
+	res, err := c.GetTransactionXml(merchantId, auth)
+
+	assert.Nil(t, err)
+	assert.NotContains(t, string(res), "<card>")
+	assert.Contains(t, string(res), "<paypage>")
+}
+
+func TestGetTransactionXmlPaymentSourceConflict(t *testing.T) {
+	sale := &Sale{
+		Id:          "1",
+		OrderId:     "5234234",
+		Amount:      40000,
+		OrderSource: "ecommerce",
+		Card:        Card{Type: "VI", Number: "4005550000081019", ExpDate: "1210"},
+		Paypage:     &Paypage{PaypageRegistrationId: "cDZJcmd1VjNlYXNaSlRMTGpocVZQY1NWVXE4Z"},
+	}
+
+	// Rejected without WithValidation.
+	c, _ := NewClient(login, password, apiBase)
+	_, err := c.GetTransactionXml(merchantId, sale)
+
+	var validationErr *ValidationError
+	if assert.True(t, errors.As(err, &validationErr)) {
+		assert.Equal(t, "sale", validationErr.Transaction)
+		assert.Equal(t, []FieldError{{Field: "card", Message: "only one of card, token or paypage may be set"}}, validationErr.Errors)
+	}
+}
+
+func TestSendErrors(t *testing.T) {
+	void := &Void{Id: "1", LitleTxnId: "1234567890123456789"}
+
+	t.Run("HTTPStatusError", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			w.Write([]byte("<html>Service Unavailable</html>"))
+		}))
+		defer server.Close()
+
+		c, _ := NewClient(login, password, server.URL)
+		_, err := c.Void(context.Background(), merchantId, void)
+
+		var statusErr *HTTPStatusError
+		assert.True(t, errors.As(err, &statusErr))
+		assert.Equal(t, http.StatusServiceUnavailable, statusErr.StatusCode)
+		assert.Equal(t, "<html>Service Unavailable</html>", statusErr.Body)
+	})
+
+	t.Run("DecodeError", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Write([]byte("<html><body>Maintenance</body>"))
+		}))
+		defer server.Close()
+
+		c, _ := NewClient(login, password, server.URL)
+		_, err := c.Void(context.Background(), merchantId, void)
+
+		var decodeErr *DecodeError
+		assert.True(t, errors.As(err, &decodeErr))
+		assert.Equal(t, "<html><body>Maintenance</body>", decodeErr.Body)
+	})
+
+	t.Run("SchemaError", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Write([]byte(`<litleOnlineResponse version="11.4" xmlns="http://www.litle.com/schema" response="1" message="Error validating xml data against the schema"/>`))
+		}))
+		defer server.Close()
+
+		c, _ := NewClient(login, password, server.URL)
+		res, err := c.Void(context.Background(), merchantId, void)
+
+		var schemaErr *SchemaError
+		assert.True(t, errors.As(err, &schemaErr))
+		assert.Equal(t, "1", schemaErr.Response)
+		assert.Equal(t, "Error validating xml data against the schema", schemaErr.Message)
+		assert.True(t, res.HasError())
+	})
+
+	t.Run("TransportError", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+		server.Close()
+
+		c, _ := NewClient(login, password, server.URL)
+		_, err := c.Void(context.Background(), merchantId, void)
+
+		var transportErr *TransportError
+		assert.True(t, errors.As(err, &transportErr))
+		assert.False(t, transportErr.Timeout())
+	})
+}
+
+func TestNewRequestUnsupportedTransaction(t *testing.T) {
+	c, _ := NewClient(login, password, apiBase)
+	_, err := c.NewRequest(context.Background(), merchantId, &BatchRequest{})
+
+	assert.NotNil(t, err)
+}
+
+func TestQueryTransactionResults(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`<litleOnlineResponse version="11.4" xmlns="http://www.litle.com/schema" response="0" message="Valid Format">` +
+			`<queryTransactionResponse id="1"><response>150</response><message>Original transaction found</message><matchCount>2</matchCount>` +
+			`<results_max10>` +
+			`<authorizationResponse id="1"><litleTxnId>82924701437133501</litleTxnId><response>000</response><message>Approved</message></authorizationResponse>` +
+			`<captureResponse id="1"><litleTxnId>82924701437133502</litleTxnId><response>000</response><message>Approved</message></captureResponse>` +
+			`</results_max10>` +
+			`</queryTransactionResponse></litleOnlineResponse>`))
+	}))
+	defer server.Close()
+
+	c, _ := NewClient(login, password, server.URL)
+	res, err := c.QueryTransaction(context.Background(), merchantId, &QueryTransaction{
+		Id:             "1",
+		OrigId:         "1",
+		OrigActionType: "A",
+	})
+
+	assert.Nil(t, err)
+	assert.True(t, res.QueryTransactionResponse.Found())
+	assert.Equal(t, 2, res.QueryTransactionResponse.MatchCount)
+	assert.Equal(t, "82924701437133501", res.QueryTransactionResponse.Results.AuthorizationResponses[0].LitleTxnId)
+	assert.Equal(t, "82924701437133502", res.QueryTransactionResponse.Results.CaptureResponses[0].LitleTxnId)
+	assert.Len(t, res.QueryTransactionResponse.Results.Responses(), 2)
+}
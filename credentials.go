@@ -0,0 +1,134 @@
+package worldpay
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+)
+
+type (
+	Credentials struct {
+		Login    string `json:"login"`
+		Password string `json:"password"`
+	}
+
+	// CredentialsProvider supplies the credentials for each request, so that
+	// rotated passwords are picked up without recreating the Client.
+	CredentialsProvider interface {
+		Credentials(ctx context.Context) (Credentials, error)
+	}
+
+	StaticCredentials Credentials
+
+	// EnvCredentials reads the credentials from environment variables on
+	// every request.
+	EnvCredentials struct {
+		LoginVar    string
+		PasswordVar string
+	}
+
+	// FileCredentials reads the credentials from a JSON file with "login"
+	// and "password" keys, and reloads it whenever its modification time
+	// changes. The file is checked at most once per Interval.
+	FileCredentials struct {
+		Path     string
+		Interval time.Duration
+
+		mu          sync.Mutex
+		credentials Credentials
+		modTime     time.Time
+		checked     time.Time
+	}
+)
+
+func (s StaticCredentials) Credentials(ctx context.Context) (Credentials, error) {
+	return Credentials(s), nil
+}
+
+func (e EnvCredentials) Credentials(ctx context.Context) (Credentials, error) {
+	creds := Credentials{
+		Login:    os.Getenv(e.LoginVar),
+		Password: os.Getenv(e.PasswordVar),
+	}
+	if creds.Login == "" || creds.Password == "" {
+		return Credentials{}, fmt.Errorf("Missing credentials in %s or %s", e.LoginVar, e.PasswordVar)
+	}
+	return creds, nil
+}
+
+func NewFileCredentials(path string) *FileCredentials {
+	return &FileCredentials{
+		Path:     path,
+		Interval: 10 * time.Second,
+	}
+}
+
+func (f *FileCredentials) Credentials(ctx context.Context) (Credentials, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	now := time.Now()
+	if !f.checked.IsZero() && now.Sub(f.checked) < f.Interval {
+		return f.credentials, nil
+	}
+
+	info, err := os.Stat(f.Path)
+	if err != nil {
+		return Credentials{}, err
+	}
+	if !info.ModTime().Equal(f.modTime) {
+		data, err := os.ReadFile(f.Path)
+		if err != nil {
+			return Credentials{}, err
+		}
+
+		var creds Credentials
+		if err := json.Unmarshal(data, &creds); err != nil {
+			return Credentials{}, err
+		}
+		if creds.Login == "" || creds.Password == "" {
+			return Credentials{}, errors.New("Missing credentials in " + f.Path)
+		}
+
+		f.credentials = creds
+		f.modTime = info.ModTime()
+	}
+	f.checked = now
+
+	return f.credentials, nil
+}
+
+// SetCredentialsProvider replaces the provider consulted for each request.
+// It is safe to call while requests are in flight.
+func (c *Client) SetCredentialsProvider(provider CredentialsProvider) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.credentialsProvider = provider
+}
+
+// SetCredentials replaces the Client's Login and Password. It is safe to
+// call while requests are in flight.
+func (c *Client) SetCredentials(login, password string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.Login = login
+	c.Password = password
+}
+
+func (c *Client) credentials(ctx context.Context) (Credentials, error) {
+	c.mu.Lock()
+	provider := c.credentialsProvider
+	creds := Credentials{Login: c.Login, Password: c.Password}
+	c.mu.Unlock()
+
+	if provider == nil {
+		return creds, nil
+	}
+	return provider.Credentials(ctx)
+}
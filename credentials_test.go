@@ -0,0 +1,92 @@
+package worldpay
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewClientCredentialsProvider(t *testing.T) {
+	_, err := NewClient("", "", apiBase)
+	assert.NotNil(t, err)
+
+	c, err := NewClient("", "", apiBase, WithCredentialsProvider(StaticCredentials{Login: "provided", Password: "secret"}))
+	assert.Nil(t, err)
+
+	res, err := c.GetTransactionXml(merchantId, &Void{Id: "1", LitleTxnId: "1"})
+	assert.Nil(t, err)
+	assert.Contains(t, string(res), "<user>provided</user>")
+	assert.Contains(t, string(res), "<password>secret</password>")
+}
+
+func TestEnvCredentials(t *testing.T) {
+	provider := EnvCredentials{LoginVar: "WORLDPAY_TEST_LOGIN", PasswordVar: "WORLDPAY_TEST_PASSWORD"}
+
+	_, err := provider.Credentials(context.Background())
+	assert.NotNil(t, err)
+
+	t.Setenv("WORLDPAY_TEST_LOGIN", "env")
+	t.Setenv("WORLDPAY_TEST_PASSWORD", "first")
+
+	creds, err := provider.Credentials(context.Background())
+	assert.Nil(t, err)
+	assert.Equal(t, Credentials{Login: "env", Password: "first"}, creds)
+
+	t.Setenv("WORLDPAY_TEST_PASSWORD", "second")
+
+	creds, _ = provider.Credentials(context.Background())
+	assert.Equal(t, "second", creds.Password)
+}
+
+func TestFileCredentials(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "credentials.json")
+	assert.Nil(t, os.WriteFile(path, []byte(`{"login": "file", "password": "first"}`), 0600))
+
+	provider := NewFileCredentials(path)
+	provider.Interval = 0
+
+	creds, err := provider.Credentials(context.Background())
+	assert.Nil(t, err)
+	assert.Equal(t, Credentials{Login: "file", Password: "first"}, creds)
+
+	assert.Nil(t, os.WriteFile(path, []byte(`{"login": "file", "password": "second"}`), 0600))
+	modTime := time.Now().Add(time.Minute)
+	assert.Nil(t, os.Chtimes(path, modTime, modTime))
+
+	creds, err = provider.Credentials(context.Background())
+	assert.Nil(t, err)
+	assert.Equal(t, "second", creds.Password)
+
+	assert.Nil(t, os.WriteFile(path, []byte(`{"login": "file"}`), 0600))
+	modTime = modTime.Add(time.Minute)
+	assert.Nil(t, os.Chtimes(path, modTime, modTime))
+
+	_, err = provider.Credentials(context.Background())
+	assert.NotNil(t, err)
+}
+
+func TestSetCredentials(t *testing.T) {
+	c, _ := NewClient(login, password, apiBase)
+
+	c.SetCredentials("rotated", "secret")
+	res, _ := c.GetTransactionXml(merchantId, &Void{Id: "1", LitleTxnId: "1"})
+	assert.Contains(t, string(res), "<user>rotated</user>")
+
+	c.SetCredentialsProvider(StaticCredentials{Login: "provided", Password: "secret"})
+	res, _ = c.GetTransactionXml(merchantId, &Void{Id: "1", LitleTxnId: "1"})
+	assert.Contains(t, string(res), "<user>provided</user>")
+}
+
+func TestCredentialsProviderError(t *testing.T) {
+	c, _ := NewClient("", "", apiBase, WithCredentialsProvider(EnvCredentials{LoginVar: "WORLDPAY_TEST_UNSET", PasswordVar: "WORLDPAY_TEST_UNSET"}))
+
+	_, err := c.NewRequest(context.Background(), merchantId, &Void{Id: "1", LitleTxnId: "1"})
+	assert.NotNil(t, err)
+
+	_, err = c.NewLitleRequest(context.Background())
+	assert.NotNil(t, err)
+}
@@ -0,0 +1,52 @@
+package worldpay
+
+// Environment identifies the Worldpay endpoints a Client sends requests to.
+// Its value is the online endpoint URL.
+type Environment string
+
+const (
+	Sandbox    Environment = "https://www.testvantivcnp.com/sandbox/communicator/online"
+	PreLive    Environment = "https://payments.vantivprelive.com/vap/communicator/online"
+	PostLive   Environment = "https://payments.vantivpostlive.com/vap/communicator/online"
+	Production Environment = "https://payments.vantivcnp.com/vap/communicator/online"
+)
+
+// NewEnvironmentClient is like NewClient, but takes a named environment
+// instead of a raw apiBase.
+func NewEnvironmentClient(login, password string, env Environment, opts ...Option) (*Client, error) {
+	return NewClient(login, password, env.OnlineURL(), opts...)
+}
+
+// OnlineURL returns the endpoint for online transactions.
+func (e Environment) OnlineURL() string {
+	return string(e)
+}
+
+// BatchAddr returns the host:port of the sFTP server batch files are
+// exchanged with, for use with NewSFTPTransport. The sandbox does not process
+// batch files, so it has no batch endpoint.
+func (e Environment) BatchAddr() string {
+	switch e {
+	case PreLive:
+		return "payments.vantivprelive.com:22"
+	case PostLive:
+		return "payments.vantivpostlive.com:22"
+	case Production:
+		return "payments.vantivcnp.com:22"
+	}
+	return ""
+}
+
+func (e Environment) String() string {
+	switch e {
+	case Sandbox:
+		return "sandbox"
+	case PreLive:
+		return "prelive"
+	case PostLive:
+		return "postlive"
+	case Production:
+		return "production"
+	}
+	return string(e)
+}
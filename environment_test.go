@@ -0,0 +1,22 @@
+package worldpay
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEnvironment(t *testing.T) {
+	assert.Equal(t, apiBase, Sandbox.OnlineURL())
+	assert.Equal(t, "", Sandbox.BatchAddr())
+	assert.Equal(t, "payments.vantivprelive.com:22", PreLive.BatchAddr())
+	assert.Equal(t, "production", Production.String())
+	assert.Equal(t, "https://example.com", Environment("https://example.com").String())
+}
+
+func TestNewEnvironmentClient(t *testing.T) {
+	c, err := NewEnvironmentClient(login, password, PostLive)
+
+	assert.Nil(t, err)
+	assert.Equal(t, "https://payments.vantivpostlive.com/vap/communicator/online", c.ApiBase)
+}
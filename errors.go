@@ -0,0 +1,110 @@
+package worldpay
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+)
+
+// maxErrorBodySnippet caps how much of an unexpected response body is kept
+// on an error. The snippet is masked like logged responses, since errors end
+// up in logs too.
+const maxErrorBodySnippet = 512
+
+type (
+	// TransportError is returned when the request could not be delivered or
+	// no response was received, e.g. on connection failures and timeouts.
+	TransportError struct {
+		Err error
+	}
+
+	// HTTPStatusError is returned when the gateway answers with a non-2xx
+	// status code.
+	HTTPStatusError struct {
+		StatusCode int
+		Status     string
+		Body       string
+	}
+
+	// SchemaError is returned when the gateway rejects the whole request,
+	// which it reports with a response attribute other than "0" on the
+	// root element.
+	SchemaError struct {
+		Response string
+		Message  string
+	}
+
+	// ValidationError is returned when a request fails local validation,
+	// before it is sent. It lists every violation found.
+	ValidationError struct {
+		Transaction string
+		Errors      []FieldError
+	}
+
+	// FieldError is a single violation, identified by the path of the
+	// offending element, e.g. "card.type".
+	FieldError struct {
+		Field   string
+		Message string
+	}
+
+	// DecodeError is returned when the response body cannot be decoded, e.g.
+	// when an HTML error page is returned instead of XML.
+	DecodeError struct {
+		Err  error
+		Body string
+	}
+)
+
+func (e *TransportError) Error() string {
+	return fmt.Sprintf("Transport error: %v", e.Err)
+}
+
+func (e *TransportError) Unwrap() error {
+	return e.Err
+}
+
+// Timeout reports whether the request failed because it timed out, in which
+// case the gateway may still have processed it.
+func (e *TransportError) Timeout() bool {
+	var netErr net.Error
+	return errors.As(e.Err, &netErr) && netErr.Timeout()
+}
+
+func (e *HTTPStatusError) Error() string {
+	return fmt.Sprintf("Unexpected HTTP status %s: %s", e.Status, e.Body)
+}
+
+func (e *SchemaError) Error() string {
+	return fmt.Sprintf("Request rejected with response %s: %s", e.Response, e.Message)
+}
+
+func (e *ValidationError) Error() string {
+	violations := make([]string, len(e.Errors))
+	for i, fe := range e.Errors {
+		violations[i] = fe.Error()
+	}
+	return fmt.Sprintf("Invalid %s: %s", e.Transaction, strings.Join(violations, "; "))
+}
+
+func (e FieldError) Error() string {
+	return e.Field + " " + e.Message
+}
+
+func (e *DecodeError) Error() string {
+	return fmt.Sprintf("Unable to decode response: %v: %s", e.Err, e.Body)
+}
+
+func (e *DecodeError) Unwrap() error {
+	return e.Err
+}
+
+func bodySnippet(body []byte) string {
+	// Mask before truncating, so that an element cut short is not missed.
+	body = MaskSensitive(body)
+	if len(body) > maxErrorBodySnippet {
+		body = body[:maxErrorBodySnippet]
+	}
+	return string(body)
+}
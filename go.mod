@@ -1,11 +1,18 @@
 module github.com/anedot/worldpay-cnp
 
-go 1.20
+go 1.21
+
+require (
+	github.com/go-playground/assert/v2 v2.2.0
+	github.com/pkg/sftp v1.13.6
+	github.com/stretchr/testify v1.8.4
+	golang.org/x/crypto v0.17.0
+)
 
 require (
 	github.com/davecgh/go-spew v1.1.1 // indirect
-	github.com/go-playground/assert/v2 v2.2.0 // indirect
+	github.com/kr/fs v0.1.0 // indirect
 	github.com/pmezard/go-difflib v1.0.0 // indirect
-	github.com/stretchr/testify v1.8.4 // indirect
+	golang.org/x/sys v0.15.0 // indirect
 	gopkg.in/yaml.v3 v3.0.1 // indirect
 )
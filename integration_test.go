@@ -7,6 +7,48 @@ import (
 	"github.com/go-playground/assert/v2"
 )
 
+func TestQueryTransaction(t *testing.T) {
+	queryTransaction := &QueryTransaction{
+		Id:             "834262",
+		ReportGroup:    "ABC Division",
+		CustomerId:     "038945",
+		OrigId:         "834262",
+		OrigActionType: "A",
+	}
+
+	c, _ := NewClient(login, password, apiBase)
+	res, _ := c.QueryTransaction(context.Background(), merchantId, queryTransaction)
+	assert.Equal(t, "11.4", res.Version)
+	assert.Equal(t, "0", res.Response)
+	assert.Equal(t, "Valid Format", res.Message)
+	assert.Equal(t, "834262", res.QueryTransactionResponse.Id)
+	assert.Equal(t, "150", res.QueryTransactionResponse.Response)
+	assert.Equal(t, "Original transaction found", res.QueryTransactionResponse.Message)
+}
+
+func TestRegisterToken(t *testing.T) {
+	registerToken := &RegisterTokenRequest{
+		Id:                "834262",
+		ReportGroup:       "ABC Division",
+		CustomerId:        "038945",
+		OrderId:           "5234234",
+		AccountNumber:     "4457119922390123",
+		CardValidationNum: "555",
+	}
+
+	c, _ := NewClient(login, password, apiBase)
+	res, _ := c.RegisterToken(context.Background(), merchantId, registerToken)
+	assert.Equal(t, "11.4", res.Version)
+	assert.Equal(t, "0", res.Response)
+	assert.Equal(t, "Valid Format", res.Message)
+	assert.Equal(t, "834262", res.RegisterTokenResponse.Id)
+	assert.Equal(t, "5234234", res.RegisterTokenResponse.OrderId)
+	assert.Equal(t, "801", res.RegisterTokenResponse.Response)
+	assert.Equal(t, "Account number was successfully registered", res.RegisterTokenResponse.Message)
+	assert.Equal(t, "445711", res.RegisterTokenResponse.Bin)
+	assert.Equal(t, "VI", res.RegisterTokenResponse.Type)
+}
+
 func TestSale(t *testing.T) {
 	t.Run("Code: 000", func(t *testing.T) {
 		sale := &Sale{
@@ -180,6 +222,112 @@ func TestSale(t *testing.T) {
 		assert.Equal(t, "1210", res.SaleResponse.AccountUpdater.NewCardInfo.ExpDate)
 	})
 
+	t.Run("with Token", func(t *testing.T) {
+		sale := &Sale{
+			Id:          "1",
+			ReportGroup: "ABC Division",
+			CustomerId:  "038945",
+			OrderId:     "5234234",
+			Amount:      40000,
+			OrderSource: "ecommerce",
+			BillToAddress: Address{
+				Name:         "John Smith",
+				AddressLine1: "100 Main St",
+				City:         "Boston",
+				State:        "MA",
+				Zip:          "12345",
+				Country:      "US",
+			},
+			Token: &Token{
+				LitleToken:        "1111000101039449",
+				ExpDate:           "1210",
+				CardValidationNum: "555",
+			},
+		}
+
+		c, _ := NewClient(login, password, apiBase)
+		res, _ := c.Sale(context.Background(), merchantId, sale)
+		assert.Equal(t, "11.4", res.Version)
+		assert.Equal(t, "0", res.Response)
+		assert.Equal(t, "Valid Format", res.Message)
+		assert.Equal(t, "1", res.SaleResponse.Id)
+		assert.Equal(t, "000", res.SaleResponse.Response)
+		assert.Equal(t, "Approved", res.SaleResponse.Message)
+	})
+
+	t.Run("with Paypage", func(t *testing.T) {
+		sale := &Sale{
+			Id:          "1",
+			ReportGroup: "ABC Division",
+			CustomerId:  "038945",
+			OrderId:     "5234234",
+			Amount:      40000,
+			OrderSource: "ecommerce",
+			BillToAddress: Address{
+				Name:         "John Smith",
+				AddressLine1: "100 Main St",
+				City:         "Boston",
+				State:        "MA",
+				Zip:          "12345",
+				Country:      "US",
+			},
+			Paypage: &Paypage{
+				PaypageRegistrationId: "cDZJcmd1VjNlYXNaSlRMTGpocVZQY1NWVXE4Z W5UTko4NU9KK3p1L1p1Vzg4YzVPQVlSUHNITG1JN2I0NzlyTg=",
+				ExpDate:               "1210",
+				CardValidationNum:     "555",
+			},
+		}
+
+		c, _ := NewClient(login, password, apiBase)
+		res, _ := c.Sale(context.Background(), merchantId, sale)
+		assert.Equal(t, "11.4", res.Version)
+		assert.Equal(t, "0", res.Response)
+		assert.Equal(t, "Valid Format", res.Message)
+		assert.Equal(t, "1", res.SaleResponse.Id)
+		assert.Equal(t, "000", res.SaleResponse.Response)
+		assert.Equal(t, "Approved", res.SaleResponse.Message)
+		assert.NotEqual(t, nil, res.SaleResponse.TokenResponse)
+	})
+
+	t.Run("with Subscription", func(t *testing.T) {
+		sale := &Sale{
+			Id:          "1",
+			ReportGroup: "ABC Division",
+			CustomerId:  "038945",
+			OrderId:     "5234234",
+			Amount:      2500,
+			OrderSource: "ecommerce",
+			BillToAddress: Address{
+				Name:         "John Smith",
+				AddressLine1: "100 Main St",
+				City:         "Boston",
+				State:        "MA",
+				Zip:          "12345",
+				Country:      "US",
+			},
+			Card: Card{
+				Type:              "VI",
+				Number:            "4005550000081000",
+				ExpDate:           "1210",
+				CardValidationNum: "555",
+			},
+			RecurringRequest: &RecurringRequest{
+				Subscription: Subscription{
+					PlanCode:  "MONTHLY_DONOR",
+					StartDate: "2023-07-01",
+				},
+			},
+		}
+
+		c, _ := NewClient(login, password, apiBase)
+		res, _ := c.Sale(context.Background(), merchantId, sale)
+		assert.Equal(t, "11.4", res.Version)
+		assert.Equal(t, "0", res.Response)
+		assert.Equal(t, "Valid Format", res.Message)
+		assert.Equal(t, "000", res.SaleResponse.Response)
+		assert.Equal(t, "000", res.SaleResponse.RecurringResponse.ResponseCode)
+	})
+
 	t.Run("with validation error", func(t *testing.T) {
 		sale := &Sale{
 			Id:          "1",
@@ -304,6 +452,62 @@ func TestAuthorization(t *testing.T) {
 	})
 }
 
+func TestAuthReversal(t *testing.T) {
+	t.Run("full reversal", func(t *testing.T) {
+		authReversal := &AuthReversal{
+			Id:          "834262",
+			ReportGroup: "ABC Division",
+			CustomerId:  "038945",
+			LitleTxnId:  "13254123434",
+		}
+
+		c, _ := NewClient(login, password, apiBase)
+		res, _ := c.AuthReversal(context.Background(), merchantId, authReversal)
+		assert.Equal(t, "11.4", res.Version)
+		assert.Equal(t, "0", res.Response)
+		assert.Equal(t, "Valid Format", res.Message)
+		assert.Equal(t, "834262", res.AuthReversalResponse.Id)
+		assert.Equal(t, "000", res.AuthReversalResponse.Response)
+		assert.Equal(t, "Approved", res.AuthReversalResponse.Message)
+	})
+
+	t.Run("partial reversal", func(t *testing.T) {
+		amount := 2500
+
+		authReversal := &AuthReversal{
+			Id:          "834262",
+			ReportGroup: "ABC Division",
+			CustomerId:  "038945",
+			LitleTxnId:  "13254123434",
+			Amount:      &amount,
+		}
+
+		c, _ := NewClient(login, password, apiBase)
+		res, _ := c.AuthReversal(context.Background(), merchantId, authReversal)
+		assert.Equal(t, "11.4", res.Version)
+		assert.Equal(t, "0", res.Response)
+		assert.Equal(t, "Valid Format", res.Message)
+		assert.Equal(t, "834262", res.AuthReversalResponse.Id)
+		assert.Equal(t, "000", res.AuthReversalResponse.Response)
+		assert.Equal(t, "Approved", res.AuthReversalResponse.Message)
+	})
+}
+
+func TestCancelSubscription(t *testing.T) {
+	cancelSubscription := &CancelSubscription{
+		SubscriptionId: "12345",
+	}
+
+	c, _ := NewClient(login, password, apiBase)
+	res, _ := c.CancelSubscription(context.Background(), merchantId, cancelSubscription)
+	assert.Equal(t, "11.4", res.Version)
+	assert.Equal(t, "0", res.Response)
+	assert.Equal(t, "Valid Format", res.Message)
+	assert.Equal(t, "12345", res.CancelSubscriptionResponse.SubscriptionId)
+	assert.Equal(t, "000", res.CancelSubscriptionResponse.Response)
+	assert.Equal(t, "Approved", res.CancelSubscriptionResponse.Message)
+}
+
 func TestCapture(t *testing.T) {
 	capture := &Capture{
 		Id:          "834262",
@@ -325,6 +529,68 @@ func TestCapture(t *testing.T) {
 	assert.Equal(t, nil, res.CaptureResponse.AccountUpdater)
 }
 
+func TestCaptureGivenAuth(t *testing.T) {
+	authAmount := 40000
+
+	captureGivenAuth := &CaptureGivenAuth{
+		Id:          "834262",
+		ReportGroup: "ABC Division",
+		CustomerId:  "038945",
+		OrderId:     "5234234",
+		AuthInformation: AuthInformation{
+			AuthDate:   "2023-06-01",
+			AuthCode:   "123456",
+			AuthAmount: &authAmount,
+		},
+		Amount:      40000,
+		OrderSource: "telephone",
+		BillToAddress: Address{
+			Name:         "John Smith",
+			AddressLine1: "100 Main St",
+			City:         "Boston",
+			State:        "MA",
+			Zip:          "12345",
+			Country:      "US",
+			Email:        "jsmith@someaddress.com",
+			Phone:        "555-123-4567",
+		},
+		Card: Card{
+			Type:              "VI",
+			Number:            "4005550000081019",
+			ExpDate:           "1210",
+			CardValidationNum: "555",
+		},
+	}
+
+	c, _ := NewClient(login, password, apiBase)
+	res, _ := c.CaptureGivenAuth(context.Background(), merchantId, captureGivenAuth)
+	assert.Equal(t, "11.4", res.Version)
+	assert.Equal(t, "0", res.Response)
+	assert.Equal(t, "Valid Format", res.Message)
+	assert.Equal(t, "834262", res.CaptureGivenAuthResponse.Id)
+	assert.Equal(t, "5234234", res.CaptureGivenAuthResponse.OrderId)
+	assert.Equal(t, "000", res.CaptureGivenAuthResponse.Response)
+	assert.Equal(t, "Approved", res.CaptureGivenAuthResponse.Message)
+}
+
+func TestCreatePlan(t *testing.T) {
+	createPlan := &CreatePlan{
+		PlanCode:     "MONTHLY_DONOR",
+		Name:         "Monthly Donor",
+		IntervalType: "MONTHLY",
+		Amount:       2500,
+	}
+
+	c, _ := NewClient(login, password, apiBase)
+	res, _ := c.CreatePlan(context.Background(), merchantId, createPlan)
+	assert.Equal(t, "11.4", res.Version)
+	assert.Equal(t, "0", res.Response)
+	assert.Equal(t, "Valid Format", res.Message)
+	assert.Equal(t, "MONTHLY_DONOR", res.CreatePlanResponse.PlanCode)
+	assert.Equal(t, "000", res.CreatePlanResponse.Response)
+	assert.Equal(t, "Approved", res.CreatePlanResponse.Message)
+}
+
 func TestCredit(t *testing.T) {
 	t.Run("with amount given", func(t *testing.T) {
 		amount := 5000
@@ -366,6 +632,76 @@ func TestCredit(t *testing.T) {
 	})
 }
 
+func TestForceCapture(t *testing.T) {
+	forceCapture := &ForceCapture{
+		Id:          "834262",
+		ReportGroup: "ABC Division",
+		CustomerId:  "038945",
+		OrderId:     "5234234",
+		Amount:      40000,
+		OrderSource: "telephone",
+		BillToAddress: Address{
+			Name:         "John Smith",
+			AddressLine1: "100 Main St",
+			City:         "Boston",
+			State:        "MA",
+			Zip:          "12345",
+			Country:      "US",
+			Email:        "jsmith@someaddress.com",
+			Phone:        "555-123-4567",
+		},
+		Card: Card{
+			Type:              "VI",
+			Number:            "4005550000081019",
+			ExpDate:           "1210",
+			CardValidationNum: "555",
+		},
+	}
+
+	c, _ := NewClient(login, password, apiBase)
+	res, _ := c.ForceCapture(context.Background(), merchantId, forceCapture)
+	assert.Equal(t, "11.4", res.Version)
+	assert.Equal(t, "0", res.Response)
+	assert.Equal(t, "Valid Format", res.Message)
+	assert.Equal(t, "834262", res.ForceCaptureResponse.Id)
+	assert.Equal(t, "5234234", res.ForceCaptureResponse.OrderId)
+	assert.Equal(t, "000", res.ForceCaptureResponse.Response)
+	assert.Equal(t, "Approved", res.ForceCaptureResponse.Message)
+}
+
+func TestUpdatePlan(t *testing.T) {
+	updatePlan := &UpdatePlan{
+		PlanCode: "MONTHLY_DONOR",
+		Active:   false,
+	}
+
+	c, _ := NewClient(login, password, apiBase)
+	res, _ := c.UpdatePlan(context.Background(), merchantId, updatePlan)
+	assert.Equal(t, "11.4", res.Version)
+	assert.Equal(t, "0", res.Response)
+	assert.Equal(t, "Valid Format", res.Message)
+	assert.Equal(t, "MONTHLY_DONOR", res.UpdatePlanResponse.PlanCode)
+	assert.Equal(t, "000", res.UpdatePlanResponse.Response)
+	assert.Equal(t, "Approved", res.UpdatePlanResponse.Message)
+}
+
+func TestUpdateSubscription(t *testing.T) {
+	updateSubscription := &UpdateSubscription{
+		SubscriptionId: "12345",
+		PlanCode:       "MONTHLY_DONOR",
+		BillingDate:    "2023-07-01",
+	}
+
+	c, _ := NewClient(login, password, apiBase)
+	res, _ := c.UpdateSubscription(context.Background(), merchantId, updateSubscription)
+	assert.Equal(t, "11.4", res.Version)
+	assert.Equal(t, "0", res.Response)
+	assert.Equal(t, "Valid Format", res.Message)
+	assert.Equal(t, "12345", res.UpdateSubscriptionResponse.SubscriptionId)
+	assert.Equal(t, "000", res.UpdateSubscriptionResponse.Response)
+	assert.Equal(t, "Approved", res.UpdateSubscriptionResponse.Message)
+}
+
 func TestVoid(t *testing.T) {
 	void := &Void{
 		Id:          "834262",
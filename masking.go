@@ -0,0 +1,34 @@
+package worldpay
+
+import "regexp"
+
+var (
+	// redactedElements are replaced entirely in logged requests and
+	// responses.
+	redactedElements = regexp.MustCompile(`<(password|cardValidationNum)>[^<]*</`)
+	// maskedElements keep their last four characters, so that logged
+	// transactions can still be told apart.
+	maskedElements = regexp.MustCompile(`<(number|accountNumber|accNum|litleToken|cnpToken|paypageRegistrationId)>([^<]*?)([^<]{0,4})</`)
+)
+
+// MaskSensitive hides passwords, card and account numbers, security codes
+// and tokens in an XML request or response, for PCI-safe logging. Numbers
+// and tokens keep their last four characters. It is applied to logged
+// requests and responses, to the body snippets kept on errors and to
+// worldpaytest cassettes.
+func MaskSensitive(data []byte) []byte {
+	data = redactedElements.ReplaceAll(data, []byte("<$1>********</"))
+	return maskedElements.ReplaceAllFunc(data, func(m []byte) []byte {
+		sub := maskedElements.FindSubmatch(m)
+
+		masked := make([]byte, 0, len(m))
+		masked = append(masked, '<')
+		masked = append(masked, sub[1]...)
+		masked = append(masked, '>')
+		for range sub[2] {
+			masked = append(masked, '*')
+		}
+		masked = append(masked, sub[3]...)
+		return append(masked, '<', '/')
+	})
+}
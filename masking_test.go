@@ -0,0 +1,90 @@
+package worldpay
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMaskSensitive(t *testing.T) {
+	masked := string(MaskSensitive([]byte(`<authentication><user>username</user><password>secret</password></authentication>` +
+		`<card><type>VI</type><number>4005550000081019</number><expDate>1210</expDate><cardValidationNum>555</cardValidationNum></card>` +
+		`<echeck><accType>Checking</accType><accNum>5186005800001012</accNum><routingNum>000010101</routingNum></echeck>` +
+		`<token><litleToken>1111000101039449</litleToken></token><paypageRegistrationId>cDZJcmd1VjNl</paypageRegistrationId>`)))
+
+	assert.Contains(t, masked, "<user>username</user>")
+	assert.Contains(t, masked, "<password>********</password>")
+	assert.Contains(t, masked, "<number>************1019</number>")
+	assert.Contains(t, masked, "<expDate>1210</expDate>")
+	assert.Contains(t, masked, "<cardValidationNum>********</cardValidationNum>")
+	assert.Contains(t, masked, "<accNum>************1012</accNum>")
+	assert.Contains(t, masked, "<routingNum>000010101</routingNum>")
+	assert.Contains(t, masked, "<litleToken>************9449</litleToken>")
+	assert.Contains(t, masked, "<paypageRegistrationId>********VjNl</paypageRegistrationId>")
+}
+
+func TestLogMasking(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`<litleOnlineResponse version="11.4" xmlns="http://www.litle.com/schema" response="0" message="Valid Format">` +
+			`<saleResponse id="1"><litleTxnId>82924701437133501</litleTxnId><response>000</response>` +
+			`<tokenResponse><litleToken>1111000101039449</litleToken></tokenResponse></saleResponse></litleOnlineResponse>`))
+	}))
+	defer server.Close()
+
+	sale := &Sale{Id: "1", Card: Card{Type: "VI", Number: "4005550000081019", ExpDate: "1210", CardValidationNum: "555"}}
+
+	var log bytes.Buffer
+	c, _ := NewClient(login, "secret", server.URL, WithLogger(&log))
+	c.Sale(context.Background(), merchantId, sale)
+
+	assert.Contains(t, log.String(), "<number>************1019</number>")
+	assert.Contains(t, log.String(), "<litleToken>************9449</litleToken>")
+	assert.NotContains(t, log.String(), "4005550000081019")
+	assert.NotContains(t, log.String(), "secret")
+	assert.NotContains(t, log.String(), "1111000101039449")
+
+	log.Reset()
+	c, _ = NewClient(login, "secret", server.URL, WithLogger(&log), WithUnmaskedLogging())
+	c.Sale(context.Background(), merchantId, sale)
+
+	assert.Contains(t, log.String(), "<number>4005550000081019</number>")
+	assert.Contains(t, log.String(), "<password>secret</password>")
+}
+
+func TestErrorBodyMasking(t *testing.T) {
+	body := `<litleOnlineResponse version="11.4" xmlns="http://www.litle.com/schema" response="0" message="Valid Format">` +
+		`<authorizationResponse id="1"><response>000</response><accountUpdater><newCardInfo><type>VI</type><number>4457000300000007</number></newCardInfo></accountUpdater>` +
+		`<tokenResponse><litleToken>1111000101039449</litleToken></tokenResponse>`
+
+	tests := []struct {
+		name   string
+		status int
+		err    interface{}
+	}{
+		{"HTTPStatusError", http.StatusInternalServerError, &HTTPStatusError{}},
+		{"DecodeError", http.StatusOK, &DecodeError{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(tt.status)
+				// The response is cut short, so it cannot be decoded.
+				w.Write([]byte(body))
+			}))
+			defer server.Close()
+
+			c, _ := NewClient(login, password, server.URL)
+			_, err := c.Authorization(context.Background(), merchantId, &Authorization{Id: "1"})
+
+			assert.IsType(t, tt.err, err)
+			assert.Contains(t, err.Error(), "<number>************0007</number>")
+			assert.NotContains(t, err.Error(), "4457000300000007")
+			assert.NotContains(t, err.Error(), "1111000101039449")
+		})
+	}
+}
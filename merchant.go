@@ -0,0 +1,85 @@
+package worldpay
+
+import (
+	"context"
+	"fmt"
+)
+
+type (
+	// Merchant is a merchant ID together with the credentials it is accessed
+	// with. Empty credentials fall back to the Client's credentials. Login
+	// and Password must be set together.
+	Merchant struct {
+		MerchantId string
+		Login      string
+		Password   string
+	}
+
+	// MerchantRouter selects the merchant a transaction is sent under. It is
+	// consulted for transactions sent without an explicit merchantId, and
+	// reports false when it has no merchant for the transaction.
+	MerchantRouter interface {
+		Route(ctx context.Context, payload interface{}) (Merchant, bool)
+	}
+
+	MerchantRouterFunc func(ctx context.Context, payload interface{}) (Merchant, bool)
+
+	// ReportGroupRouter routes transactions by their reportGroup attribute.
+	ReportGroupRouter map[string]Merchant
+
+	// CurrencyRouter routes transactions by the currency attached to the
+	// request context with ContextWithCurrency.
+	CurrencyRouter map[string]Merchant
+
+	currencyKey struct{}
+)
+
+func (f MerchantRouterFunc) Route(ctx context.Context, payload interface{}) (Merchant, bool) {
+	return f(ctx, payload)
+}
+
+func (r ReportGroupRouter) Route(ctx context.Context, payload interface{}) (Merchant, bool) {
+	m, ok := r[describeTransaction(payload).ReportGroup]
+	return m, ok
+}
+
+func (r CurrencyRouter) Route(ctx context.Context, payload interface{}) (Merchant, bool) {
+	currency, _ := ctx.Value(currencyKey{}).(string)
+	m, ok := r[currency]
+	return m, ok
+}
+
+// ContextWithCurrency returns a copy of ctx carrying the currency of the
+// transaction, for use by CurrencyRouter.
+func ContextWithCurrency(ctx context.Context, currency string) context.Context {
+	return context.WithValue(ctx, currencyKey{}, currency)
+}
+
+// merchant resolves the merchant for a transaction. An explicit merchantId
+// takes precedence over the Router, which takes precedence over the Client's
+// MerchantId.
+func (c *Client) merchant(ctx context.Context, merchantId string, payload interface{}) (Merchant, error) {
+	m := Merchant{MerchantId: merchantId}
+
+	if merchantId == "" && c.Router != nil {
+		if routed, ok := c.Router.Route(ctx, payload); ok {
+			m = routed
+		}
+	}
+
+	if m.MerchantId == "" {
+		m.MerchantId = c.MerchantId
+	}
+	if (m.Login == "") != (m.Password == "") {
+		return Merchant{}, fmt.Errorf("Merchant %s has partial credentials: login and password must be set together", m.MerchantId)
+	}
+	if m.Login == "" {
+		creds, err := c.credentials(ctx)
+		if err != nil {
+			return Merchant{}, err
+		}
+		m.Login, m.Password = creds.Login, creds.Password
+	}
+
+	return m, nil
+}
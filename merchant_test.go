@@ -0,0 +1,59 @@
+package worldpay
+
+import (
+	"context"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDefaultMerchant(t *testing.T) {
+	c, _ := NewClient(login, password, apiBase, WithDefaultMerchant("200"))
+
+	res, _ := c.GetTransactionXml("", &Void{Id: "1", LitleTxnId: "1"})
+	assert.Contains(t, string(res), `merchantId="200"`)
+
+	res, _ = c.GetTransactionXml(merchantId, &Void{Id: "1", LitleTxnId: "1"})
+	assert.Contains(t, string(res), `merchantId="100"`)
+}
+
+func TestReportGroupRouter(t *testing.T) {
+	c, _ := NewClient(login, password, apiBase,
+		WithDefaultMerchant("200"),
+		WithMerchantRouter(ReportGroupRouter{
+			"Donations": {MerchantId: "300", Login: "donations", Password: "secret"},
+		}),
+	)
+
+	res, _ := c.GetTransactionXml("", &Void{Id: "1", ReportGroup: "Donations", LitleTxnId: "1"})
+	assert.Contains(t, string(res), `merchantId="300"`)
+	assert.Contains(t, string(res), "<user>donations</user>")
+
+	res, _ = c.GetTransactionXml("", &Void{Id: "1", ReportGroup: "Other", LitleTxnId: "1"})
+	assert.Contains(t, string(res), `merchantId="200"`)
+	assert.Contains(t, string(res), "<user>username</user>")
+}
+
+func TestRouterPartialCredentials(t *testing.T) {
+	c, _ := NewClient(login, password, apiBase, WithMerchantRouter(ReportGroupRouter{
+		"Donations": {MerchantId: "300", Login: "donations"},
+	}))
+
+	_, err := c.GetTransactionXml("", &Void{Id: "1", ReportGroup: "Donations", LitleTxnId: "1"})
+	assert.EqualError(t, err, "Merchant 300 has partial credentials: login and password must be set together")
+}
+
+func TestCurrencyRouter(t *testing.T) {
+	c, _ := NewClient(login, password, apiBase, WithMerchantRouter(CurrencyRouter{
+		"EUR": {MerchantId: "400"},
+	}))
+
+	ctx := ContextWithCurrency(context.Background(), "EUR")
+	req, err := c.NewRequest(ctx, "", &Void{Id: "1", LitleTxnId: "1"})
+	assert.Nil(t, err)
+
+	body, _ := io.ReadAll(req.Body)
+	assert.Contains(t, string(body), `merchantId="400"`)
+	assert.Contains(t, string(body), "<user>username</user>")
+}
@@ -0,0 +1,111 @@
+package worldpay
+
+import (
+	"io"
+	"log/slog"
+	"net/http"
+	"time"
+)
+
+// Option configures a Client in NewClient.
+type Option func(*Client)
+
+// WithHTTPClient sets the HTTP client used to send requests. A nil client
+// leaves the default in place.
+func WithHTTPClient(httpClient *http.Client) Option {
+	return func(c *Client) {
+		c.Client = httpClient
+	}
+}
+
+// WithTimeout sets the timeout for each HTTP request. It applies to the
+// client given with WithHTTPClient, if any, without modifying it.
+func WithTimeout(timeout time.Duration) Option {
+	return func(c *Client) {
+		c.timeout = timeout
+	}
+}
+
+// WithLogger sets the writer requests and responses are logged to. Sensitive
+// values are masked, see WithUnmaskedLogging.
+func WithLogger(log io.Writer) Option {
+	return func(c *Client) {
+		c.Log = log
+	}
+}
+
+// WithUnmaskedLogging disables masking of passwords, card and account
+// numbers, security codes and tokens in logged requests and responses. It
+// must not be used outside local debugging.
+func WithUnmaskedLogging() Option {
+	return func(c *Client) {
+		c.LogUnmasked = true
+	}
+}
+
+// WithStructuredLogger emits one record per online transaction to logger,
+// with its type, merchantId, id, orderId, litleTxnId, response code, HTTP
+// status, latency and number of attempts. No card data is included: the
+// message of a failed transaction's error is masked like the logged requests.
+func WithStructuredLogger(logger *slog.Logger) Option {
+	return func(c *Client) {
+		c.StructuredLogger = logger
+	}
+}
+
+// WithEnvironment sends requests to env instead of the apiBase passed to
+// NewClient, which may then be left empty.
+func WithEnvironment(env Environment) Option {
+	return func(c *Client) {
+		c.ApiBase = string(env)
+	}
+}
+
+// WithCredentialsProvider sets the provider consulted for credentials on each
+// request, in place of the login and password passed to NewClient, which may
+// then be left empty.
+func WithCredentialsProvider(provider CredentialsProvider) Option {
+	return func(c *Client) {
+		c.credentialsProvider = provider
+	}
+}
+
+// WithSchemaVersion sets the cnpAPI schema version requests are sent with.
+// It defaults to SchemaVersion11.
+func WithSchemaVersion(version SchemaVersion) Option {
+	return func(c *Client) {
+		c.Schema = version
+	}
+}
+
+// WithValidation validates each request locally before it is sent, so that
+// invalid requests fail with a *ValidationError instead of a schema error
+// from the gateway.
+func WithValidation() Option {
+	return func(c *Client) {
+		c.ValidateRequests = true
+	}
+}
+
+// WithDefaultMerchant sets the client's MerchantId, which is used when a
+// transaction is sent without a merchantId.
+func WithDefaultMerchant(merchantId string) Option {
+	return func(c *Client) {
+		c.MerchantId = merchantId
+	}
+}
+
+// WithMerchantRouter sets the router used to pick the merchant for
+// transactions sent without an explicit merchantId.
+func WithMerchantRouter(router MerchantRouter) Option {
+	return func(c *Client) {
+		c.Router = router
+	}
+}
+
+// WithUserAgent sets the User-Agent header sent with every request.
+func WithUserAgent(userAgent string) Option {
+	return func(c *Client) {
+		c.UserAgent = userAgent
+	}
+}
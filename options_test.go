@@ -0,0 +1,64 @@
+package worldpay
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewClientOptions(t *testing.T) {
+	httpClient := &http.Client{}
+	log := &bytes.Buffer{}
+
+	c, err := NewClient(login, password, "",
+		WithHTTPClient(httpClient),
+		WithTimeout(10*time.Second),
+		WithLogger(log),
+		WithEnvironment(Sandbox),
+		WithDefaultMerchant(merchantId),
+		WithUserAgent("worldpay-test"),
+	)
+
+	assert.Nil(t, err)
+	assert.Equal(t, apiBase, c.ApiBase)
+	assert.Equal(t, merchantId, c.MerchantId)
+	assert.Equal(t, log, c.Log)
+	assert.Equal(t, 10*time.Second, c.Client.Timeout)
+	assert.Equal(t, time.Duration(0), httpClient.Timeout)
+}
+
+func TestNewClientNilHTTPClient(t *testing.T) {
+	c, err := NewClient(login, password, apiBase,
+		WithHTTPClient(nil),
+		WithTimeout(10*time.Second),
+	)
+
+	assert.Nil(t, err)
+	assert.NotNil(t, c.Client)
+	assert.Equal(t, 10*time.Second, c.Client.Timeout)
+}
+
+func TestNewClientMissingApiBase(t *testing.T) {
+	_, err := NewClient(login, password, "", WithUserAgent("worldpay-test"))
+
+	assert.NotNil(t, err)
+}
+
+func TestWithUserAgent(t *testing.T) {
+	var userAgent string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		userAgent = r.UserAgent()
+		w.Write([]byte(`<litleOnlineResponse version="11.4" xmlns="http://www.litle.com/schema" response="0" message="Valid Format"/>`))
+	}))
+	defer server.Close()
+
+	c, _ := NewClient(login, password, server.URL, WithUserAgent("worldpay-test"))
+	c.Void(context.Background(), merchantId, &Void{Id: "1", LitleTxnId: "1"})
+
+	assert.Equal(t, "worldpay-test", userAgent)
+}
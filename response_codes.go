@@ -0,0 +1,598 @@
+package worldpay
+
+type (
+	ResponseCategory int
+
+	// TransactionResponse is implemented by every transaction response
+	// type, such as *SaleResponse and *CaptureResponse.
+	TransactionResponse interface {
+		Approved() bool
+		SoftDecline() bool
+		HardDecline() bool
+		Retryable() bool
+	}
+
+	// ResponseCode describes a transaction level response code returned in
+	// the response element of every transaction response.
+	ResponseCode struct {
+		Code     string
+		Message  string
+		Category ResponseCategory
+		// Retryable reports whether the transaction may succeed if it is
+		// submitted again shortly under a new Id. Resubmitting it with the
+		// same Id is answered with the original response.
+		Retryable bool
+	}
+)
+
+const (
+	CategoryUnknown ResponseCategory = iota
+	CategoryApproved
+	CategorySoftDecline
+	CategoryHardDecline
+	CategoryReferral
+	CategoryError
+	// CategoryQuery is the outcome of a queryTransaction, which reports
+	// whether the original transaction was found rather than approving or
+	// declining anything. See QueryTransactionResponse.Found.
+	CategoryQuery
+)
+
+var responseCodes = map[string]ResponseCode{
+	"000": {Code: "000", Message: "Approved", Category: CategoryApproved},
+	"001": {Code: "001", Message: "Transaction Received", Category: CategoryApproved},
+	"010": {Code: "010", Message: "Partially Approved", Category: CategoryApproved},
+	"011": {Code: "011", Message: "Offline Approval", Category: CategoryApproved},
+	"013": {Code: "013", Message: "Offline Approval (Unable to go online)", Category: CategoryApproved},
+	"100": {Code: "100", Message: "Processing Network Unavailable", Category: CategorySoftDecline, Retryable: true},
+	"101": {Code: "101", Message: "Issuer Unavailable", Category: CategorySoftDecline, Retryable: true},
+	"102": {Code: "102", Message: "Re-submit Transaction", Category: CategorySoftDecline, Retryable: true},
+	"103": {Code: "103", Message: "Merchant not configured for processing at this site", Category: CategoryError},
+	"108": {Code: "108", Message: "Try Again Later", Category: CategorySoftDecline, Retryable: true},
+	"110": {Code: "110", Message: "Insufficient Funds", Category: CategorySoftDecline},
+	"111": {Code: "111", Message: "Authorization amount has already been depleted", Category: CategoryHardDecline},
+	"120": {Code: "120", Message: "Call Issuer", Category: CategoryReferral},
+	"121": {Code: "121", Message: "Call AMEX", Category: CategoryReferral},
+	"122": {Code: "122", Message: "Call Diners Club", Category: CategoryReferral},
+	"123": {Code: "123", Message: "Call Discover", Category: CategoryReferral},
+	"124": {Code: "124", Message: "Call JBS", Category: CategoryReferral},
+	"125": {Code: "125", Message: "Call Visa/MasterCard", Category: CategoryReferral},
+	"126": {Code: "126", Message: "Call Issuer - Update Cardholder Data", Category: CategoryReferral},
+	"127": {Code: "127", Message: "Exceeds Approval Amount Limit", Category: CategorySoftDecline},
+	"130": {Code: "130", Message: "Call Indicated Number", Category: CategoryReferral},
+	"140": {Code: "140", Message: "Update Cardholder Data", Category: CategorySoftDecline},
+	"150": {Code: "150", Message: "Original transaction found", Category: CategoryQuery},
+	"151": {Code: "151", Message: "Original transaction not found", Category: CategoryQuery},
+	"152": {Code: "152", Message: "Original transaction found but response not yet available", Category: CategoryQuery},
+	"191": {Code: "191", Message: "The merchant is not registered in the update program", Category: CategoryError},
+	"192": {Code: "192", Message: "Merchant not certified/enabled for IIAS", Category: CategoryError},
+	"206": {Code: "206", Message: "Issuer Generated Error", Category: CategorySoftDecline},
+	"207": {Code: "207", Message: "Pickup card - Other than Lost/Stolen", Category: CategoryHardDecline},
+	"209": {Code: "209", Message: "Invalid Amount", Category: CategoryHardDecline},
+	"211": {Code: "211", Message: "Reversal Unsuccessful", Category: CategoryHardDecline},
+	"212": {Code: "212", Message: "Missing Data", Category: CategoryError},
+	"213": {Code: "213", Message: "Pickup Card - Lost Card", Category: CategoryHardDecline},
+	"214": {Code: "214", Message: "Pickup Card - Stolen Card", Category: CategoryHardDecline},
+	"215": {Code: "215", Message: "Restricted Card", Category: CategoryHardDecline},
+	"216": {Code: "216", Message: "Invalid Deactivate", Category: CategoryHardDecline},
+	"217": {Code: "217", Message: "Card Already Active", Category: CategoryHardDecline},
+	"218": {Code: "218", Message: "Card Not Active", Category: CategoryHardDecline},
+	"219": {Code: "219", Message: "Card Already Deactivate", Category: CategoryHardDecline},
+	"221": {Code: "221", Message: "Over Max Balance", Category: CategoryHardDecline},
+	"222": {Code: "222", Message: "Invalid Activate", Category: CategoryHardDecline},
+	"223": {Code: "223", Message: "No transaction Found for Reversal", Category: CategoryHardDecline},
+	"226": {Code: "226", Message: "Incorrect CVV", Category: CategoryHardDecline},
+	"229": {Code: "229", Message: "Illegal Transaction", Category: CategoryHardDecline},
+	"251": {Code: "251", Message: "Duplicate Transaction", Category: CategoryHardDecline},
+	"252": {Code: "252", Message: "System Error", Category: CategoryError, Retryable: true},
+	"253": {Code: "253", Message: "Deconverted BIN", Category: CategoryHardDecline},
+	"254": {Code: "254", Message: "Merchant Depleted", Category: CategoryHardDecline},
+	"255": {Code: "255", Message: "Gift Card - Escheated", Category: CategoryHardDecline},
+	"256": {Code: "256", Message: "Invalid Reversal Type for Credit Card Transaction", Category: CategoryError},
+	"257": {Code: "257", Message: "System Error (message format error)", Category: CategoryError},
+	"258": {Code: "258", Message: "System Error (cannot process)", Category: CategoryError, Retryable: true},
+	"301": {Code: "301", Message: "Invalid Account Number", Category: CategoryHardDecline},
+	"302": {Code: "302", Message: "Account Number Does Not Match Payment Type", Category: CategoryHardDecline},
+	"303": {Code: "303", Message: "Pick Up Card", Category: CategoryHardDecline},
+	"304": {Code: "304", Message: "Lost/Stolen Card", Category: CategoryHardDecline},
+	"305": {Code: "305", Message: "Expired Card", Category: CategoryHardDecline},
+	"306": {Code: "306", Message: "Authorization has expired; no need to reverse", Category: CategoryHardDecline},
+	"307": {Code: "307", Message: "Restricted Card", Category: CategoryHardDecline},
+	"308": {Code: "308", Message: "Restricted Card - Chargeback", Category: CategoryHardDecline},
+	"309": {Code: "309", Message: "Restricted Card - Prepaid Card Filtering Service", Category: CategoryHardDecline},
+	"310": {Code: "310", Message: "Invalid track data", Category: CategoryHardDecline},
+	"311": {Code: "311", Message: "Deposit is already referenced by a chargeback", Category: CategoryHardDecline},
+	"312": {Code: "312", Message: "Restricted Card - International Card Filtering Service", Category: CategoryHardDecline},
+	"313": {Code: "313", Message: "International filtering for issuing card country", Category: CategoryHardDecline},
+	"315": {Code: "315", Message: "Restricted Card - Auth Fraud Velocity Filtering Service", Category: CategoryHardDecline},
+	"316": {Code: "316", Message: "Automatic Refund Already Issued", Category: CategoryHardDecline},
+	"317": {Code: "317", Message: "Restricted Card - Card Unvaulted", Category: CategoryHardDecline},
+	"318": {Code: "318", Message: "Restricted Card - Auth Fraud Advice Filtering Service", Category: CategoryHardDecline},
+	"319": {Code: "319", Message: "Restricted Card - Fraud AVS Filtering Service", Category: CategoryHardDecline},
+	"320": {Code: "320", Message: "Invalid Expiration Date", Category: CategoryHardDecline},
+	"321": {Code: "321", Message: "Invalid Merchant", Category: CategoryError},
+	"322": {Code: "322", Message: "Invalid Transaction", Category: CategoryHardDecline},
+	"323": {Code: "323", Message: "No such issuer", Category: CategoryHardDecline},
+	"324": {Code: "324", Message: "Invalid Pin", Category: CategoryHardDecline},
+	"325": {Code: "325", Message: "Transaction not allowed at terminal", Category: CategoryHardDecline},
+	"326": {Code: "326", Message: "Exceeds number of PIN entries", Category: CategoryHardDecline},
+	"327": {Code: "327", Message: "Cardholder transaction not permitted", Category: CategoryHardDecline},
+	"328": {Code: "328", Message: "Cardholder requested that recurring or installment payment be stopped", Category: CategoryHardDecline},
+	"330": {Code: "330", Message: "Invalid Payment Type", Category: CategoryHardDecline},
+	"331": {Code: "331", Message: "Invalid POS Capability for Cardholder Authorized Terminal Transaction", Category: CategoryHardDecline},
+	"332": {Code: "332", Message: "Invalid POS Cardholder ID for Cardholder Authorized Terminal Transaction", Category: CategoryHardDecline},
+	"335": {Code: "335", Message: "This method of payment does not support authorization reversals", Category: CategoryHardDecline},
+	"336": {Code: "336", Message: "Reversal amount does not match Authorization amount", Category: CategoryHardDecline},
+	"340": {Code: "340", Message: "Invalid Amount", Category: CategoryHardDecline},
+	"341": {Code: "341", Message: "Invalid Healthcare Amounts", Category: CategoryHardDecline},
+	"346": {Code: "346", Message: "Invalid billing descriptor prefix", Category: CategoryError},
+	"347": {Code: "347", Message: "Invalid billing descriptor", Category: CategoryError},
+	"348": {Code: "348", Message: "Invalid Report Group", Category: CategoryError},
+	"349": {Code: "349", Message: "Do Not Honor", Category: CategoryHardDecline},
+	"350": {Code: "350", Message: "Generic Decline", Category: CategoryHardDecline},
+	"351": {Code: "351", Message: "Decline - Request Positive ID", Category: CategoryHardDecline},
+	"352": {Code: "352", Message: "Decline CVV2/CID Fail", Category: CategoryHardDecline},
+	"353": {Code: "353", Message: "Merchant requested decline due to AVS result", Category: CategoryHardDecline},
+	"354": {Code: "354", Message: "3-D Secure transaction not supported by merchant", Category: CategoryHardDecline},
+	"355": {Code: "355", Message: "Failed velocity check", Category: CategoryHardDecline},
+	"356": {Code: "356", Message: "Invalid purchase level III, the transaction contained bad or missing data", Category: CategoryHardDecline},
+	"360": {Code: "360", Message: "No transaction found with specified litleTxnId", Category: CategoryHardDecline},
+	"361": {Code: "361", Message: "Authorization no longer available", Category: CategoryHardDecline},
+	"362": {Code: "362", Message: "Transaction Not Voided - Already Settled", Category: CategoryHardDecline},
+	"363": {Code: "363", Message: "Auto-void on refund", Category: CategoryHardDecline},
+	"364": {Code: "364", Message: "Invalid Account number - original or NOC updated eCheck account required", Category: CategoryHardDecline},
+	"365": {Code: "365", Message: "Total credit amount exceeds capture amount", Category: CategoryHardDecline},
+	"366": {Code: "366", Message: "Exceed the threshold for sending redeposits", Category: CategoryHardDecline},
+	"367": {Code: "367", Message: "Deposit has not been returned for insufficient/non-sufficient funds", Category: CategoryHardDecline},
+	"368": {Code: "368", Message: "Invalid check number", Category: CategoryHardDecline},
+	"369": {Code: "369", Message: "Redeposit against invalid transaction type", Category: CategoryHardDecline},
+	"370": {Code: "370", Message: "Internal System Error - Call Litle", Category: CategoryError, Retryable: true},
+	"372": {Code: "372", Message: "Soft Decline - Auto Recycling In Progress", Category: CategorySoftDecline},
+	"373": {Code: "373", Message: "Hard Decline - Auto Recycling Complete", Category: CategoryHardDecline},
+	"375": {Code: "375", Message: "Merchant is not enabled for surcharging", Category: CategoryError},
+	"376": {Code: "376", Message: "This method of payment does not support surcharging", Category: CategoryError},
+	"377": {Code: "377", Message: "Surcharge is not valid for debit or prepaid cards", Category: CategoryError},
+	"378": {Code: "378", Message: "Surcharge cannot exceed 4% of the sale amount", Category: CategoryError},
+	"380": {Code: "380", Message: "Secondary amount cannot exceed the sale amount", Category: CategoryError},
+	"381": {Code: "381", Message: "This method of payment does not support secondary amount", Category: CategoryError},
+	"382": {Code: "382", Message: "Secondary amount cannot be less than zero", Category: CategoryError},
+	"383": {Code: "383", Message: "Partial transaction is not supported when including a secondary amount", Category: CategoryError},
+	"384": {Code: "384", Message: "Secondary amount required on partial refund when used on deposit", Category: CategoryError},
+	"385": {Code: "385", Message: "Secondary amount not allowed on refund if not included on deposit", Category: CategoryError},
+	"401": {Code: "401", Message: "Invalid E-mail", Category: CategoryHardDecline},
+	"469": {Code: "469", Message: "Invalid Recurring Request - See Recurring Response for Details", Category: CategoryError},
+	"470": {Code: "470", Message: "Approved - Recurring Subscription Created", Category: CategoryApproved},
+	"471": {Code: "471", Message: "Parent Transaction Declined - Recurring Subscription Not Created", Category: CategoryHardDecline},
+	"472": {Code: "472", Message: "Invalid Plan Code", Category: CategoryError},
+	"473": {Code: "473", Message: "Scheduled Recurring Payment Processed", Category: CategoryApproved},
+	"475": {Code: "475", Message: "Invalid Subscription Id", Category: CategoryError},
+	"476": {Code: "476", Message: "Add On Code Already Exists", Category: CategoryError},
+	"477": {Code: "477", Message: "Duplicate Add On Codes in Requests", Category: CategoryError},
+	"478": {Code: "478", Message: "No Matching Add On Code for the Subscription", Category: CategoryError},
+	"480": {Code: "480", Message: "No Matching Discount Code for the Subscription", Category: CategoryError},
+	"481": {Code: "481", Message: "Duplicate Discount Codes in Request", Category: CategoryError},
+	"482": {Code: "482", Message: "Invalid Start Date", Category: CategoryError},
+	"483": {Code: "483", Message: "Merchant Not Registered for Recurring Engine", Category: CategoryError},
+	"484": {Code: "484", Message: "Insufficient data to update subscription", Category: CategoryError},
+	"485": {Code: "485", Message: "Invalid Billing Date", Category: CategoryError},
+	"486": {Code: "486", Message: "Discount Code Already Exists", Category: CategoryError},
+	"487": {Code: "487", Message: "Plan Code Already Exists", Category: CategoryError},
+	"550": {Code: "550", Message: "Restricted Device or IP - ThreatMetrix Fraud Score Below Threshold", Category: CategoryHardDecline},
+	"601": {Code: "601", Message: "Soft Decline - Primary Funding Source Failed", Category: CategorySoftDecline},
+	"602": {Code: "602", Message: "Soft Decline - Buyer has alternate funding source", Category: CategorySoftDecline},
+	"610": {Code: "610", Message: "Hard Decline - Invalid Billing Agreement Id", Category: CategoryHardDecline},
+	"611": {Code: "611", Message: "Hard Decline - Primary Funding Source Failed", Category: CategoryHardDecline},
+	"612": {Code: "612", Message: "Hard Decline - Issue with Paypal Account", Category: CategoryHardDecline},
+	"613": {Code: "613", Message: "Hard Decline - PayPal authorization ID missing", Category: CategoryHardDecline},
+	"614": {Code: "614", Message: "Hard Decline - confirmed email address is not available", Category: CategoryHardDecline},
+	"615": {Code: "615", Message: "Hard Decline - PayPal buyer account denied", Category: CategoryHardDecline},
+	"616": {Code: "616", Message: "Hard Decline - PayPal buyer account restricted", Category: CategoryHardDecline},
+	"617": {Code: "617", Message: "Hard Decline - PayPal order has been voided, expired, or completed", Category: CategoryHardDecline},
+	"618": {Code: "618", Message: "Hard Decline - issue with PayPal refund", Category: CategoryHardDecline},
+	"619": {Code: "619", Message: "Hard Decline - PayPal credentials issue", Category: CategoryHardDecline},
+	"620": {Code: "620", Message: "Hard Decline - PayPal authorization voided or expired", Category: CategoryHardDecline},
+	"621": {Code: "621", Message: "Hard Decline - required PayPal parameter missing", Category: CategoryHardDecline},
+	"622": {Code: "622", Message: "Hard Decline - PayPal transaction ID or auth ID is invalid", Category: CategoryHardDecline},
+	"623": {Code: "623", Message: "Hard Decline - Exceeded maximum number of PayPal authorization attempts", Category: CategoryHardDecline},
+	"624": {Code: "624", Message: "Hard Decline - Transaction amount exceeds merchant's PayPal account limit", Category: CategoryHardDecline},
+	"625": {Code: "625", Message: "Hard Decline - PayPal funding sources unavailable", Category: CategoryHardDecline},
+	"626": {Code: "626", Message: "Hard Decline - issue with PayPal primary funding source", Category: CategoryHardDecline},
+	"627": {Code: "627", Message: "Hard Decline - PayPal profile does not allow this transaction type", Category: CategoryHardDecline},
+	"628": {Code: "628", Message: "Internal System Error with PayPal - Contact Litle", Category: CategoryError},
+	"629": {Code: "629", Message: "Hard Decline - Contact PayPal consumer for another payment method", Category: CategoryHardDecline},
+	"637": {Code: "637", Message: "Invalid terminal Id", Category: CategoryError},
+	"640": {Code: "640", Message: "PINless Debit processing not supported for non-recurring transactions", Category: CategoryError},
+	"641": {Code: "641", Message: "PINless Debit processing not supported for partial auths", Category: CategoryError},
+	"642": {Code: "642", Message: "Merchant not configured for PINless Debit processing", Category: CategoryError},
+	"643": {Code: "643", Message: "Decline - Customer Cancellation", Category: CategoryHardDecline},
+	"701": {Code: "701", Message: "Under 18 years old", Category: CategoryHardDecline},
+	"702": {Code: "702", Message: "Bill to outside USA", Category: CategoryHardDecline},
+	"703": {Code: "703", Message: "Bill to address is not equal to ship to address", Category: CategoryHardDecline},
+	"704": {Code: "704", Message: "Declined, foreign currency, must be USD", Category: CategoryHardDecline},
+	"705": {Code: "705", Message: "On negative file", Category: CategoryHardDecline},
+	"706": {Code: "706", Message: "Blocked agreement", Category: CategoryHardDecline},
+	"707": {Code: "707", Message: "Insufficient buying power", Category: CategoryHardDecline},
+	"708": {Code: "708", Message: "Invalid Data", Category: CategoryHardDecline},
+	"709": {Code: "709", Message: "Invalid Data - data elements missing", Category: CategoryHardDecline},
+	"710": {Code: "710", Message: "Invalid Data - data format error", Category: CategoryHardDecline},
+	"711": {Code: "711", Message: "Invalid Data - Invalid T&C version", Category: CategoryHardDecline},
+	"712": {Code: "712", Message: "Duplicate transaction", Category: CategoryHardDecline},
+	"713": {Code: "713", Message: "Verify billing address", Category: CategoryHardDecline},
+	"714": {Code: "714", Message: "Inactive Account", Category: CategoryHardDecline},
+	"716": {Code: "716", Message: "Invalid Auth", Category: CategoryHardDecline},
+	"717": {Code: "717", Message: "Authorization already exists for the order", Category: CategoryHardDecline},
+	"801": {Code: "801", Message: "Account number was successfully registered", Category: CategoryApproved},
+	"802": {Code: "802", Message: "Account number was previously registered", Category: CategoryApproved},
+	"805": {Code: "805", Message: "Card Validation Number Updated", Category: CategoryApproved},
+	"820": {Code: "820", Message: "Credit card number was invalid", Category: CategoryHardDecline},
+	"821": {Code: "821", Message: "Merchant is not authorized for tokens", Category: CategoryError},
+	"822": {Code: "822", Message: "Token was not found", Category: CategoryHardDecline},
+	"823": {Code: "823", Message: "Token Invalid", Category: CategoryHardDecline},
+	"835": {Code: "835", Message: "Capability not allowed", Category: CategoryError},
+	"850": {Code: "850", Message: "Tax Billing only allowed for MCC 9311", Category: CategoryError},
+	"851": {Code: "851", Message: "MCC 9311 requires taxType element", Category: CategoryError},
+	"852": {Code: "852", Message: "Debt repayment only allowed for VI transactions on MCCs 6012 and 6051", Category: CategoryError},
+	"861": {Code: "861", Message: "Routing Number did not match one on file for Token", Category: CategoryHardDecline},
+	"877": {Code: "877", Message: "Invalid paypage registration id", Category: CategoryHardDecline},
+	"878": {Code: "878", Message: "Expired paypage registration id", Category: CategoryHardDecline},
+	"879": {Code: "879", Message: "Merchant is not authorized for Paypage", Category: CategoryError},
+	"890": {Code: "890", Message: "Maximum number of updates for this token exceeded", Category: CategoryHardDecline},
+	"891": {Code: "891", Message: "Too many tokens created for existing namespace", Category: CategoryHardDecline},
+	"895": {Code: "895", Message: "PIN validation not possible", Category: CategoryHardDecline},
+	"898": {Code: "898", Message: "Generic token registration error", Category: CategoryError},
+	"899": {Code: "899", Message: "Generic token use error", Category: CategoryError},
+	"900": {Code: "900", Message: "Invalid Bank Routing Number", Category: CategoryHardDecline},
+	"950": {Code: "950", Message: "Decline - Negative Information on File", Category: CategoryHardDecline},
+	"951": {Code: "951", Message: "Absolute Decline", Category: CategoryHardDecline},
+	"952": {Code: "952", Message: "The Merchant Profile does not allow the requested operation", Category: CategoryError},
+	"953": {Code: "953", Message: "The account cannot accept ACH transactions", Category: CategoryHardDecline},
+	"954": {Code: "954", Message: "The account cannot accept ACH transactions or site drafts", Category: CategoryHardDecline},
+	"955": {Code: "955", Message: "Amount greater than limit specified in the Merchant Profile", Category: CategoryHardDecline},
+	"956": {Code: "956", Message: "Merchant is not authorized to perform eCheck Verification transactions", Category: CategoryError},
+	"957": {Code: "957", Message: "First Name and Last Name required for eCheck Verifications", Category: CategoryError},
+	"958": {Code: "958", Message: "Company Name required for corporate account for eCheck Verifications", Category: CategoryError},
+	"959": {Code: "959", Message: "Phone number required for eCheck Verifications", Category: CategoryError},
+	"961": {Code: "961", Message: "Card Brand token not supported", Category: CategoryError},
+	"962": {Code: "962", Message: "Private Label Card not supported", Category: CategoryError},
+	"965": {Code: "965", Message: "Allowed daily direct credit limit exceeded", Category: CategoryHardDecline},
+}
+
+// LookupResponseCode returns the catalog entry for a transaction response
+// code. Unknown codes are reported with CategoryUnknown.
+func LookupResponseCode(code string) (ResponseCode, bool) {
+	rc, ok := responseCodes[code]
+	if !ok {
+		return ResponseCode{Code: code, Category: CategoryUnknown}, false
+	}
+	return rc, true
+}
+
+func (c ResponseCategory) String() string {
+	switch c {
+	case CategoryApproved:
+		return "approved"
+	case CategorySoftDecline:
+		return "soft decline"
+	case CategoryHardDecline:
+		return "hard decline"
+	case CategoryReferral:
+		return "referral"
+	case CategoryError:
+		return "error"
+	case CategoryQuery:
+		return "query"
+	default:
+		return "unknown"
+	}
+}
+
+func responseCategory(code string) ResponseCategory {
+	rc, _ := LookupResponseCode(code)
+	return rc.Category
+}
+
+func responseRetryable(code string) bool {
+	rc, _ := LookupResponseCode(code)
+	return rc.Retryable
+}
+
+func (r *AuthorizationResponse) Approved() bool {
+	return r != nil && responseCategory(r.Response) == CategoryApproved
+}
+
+func (r *AuthorizationResponse) SoftDecline() bool {
+	return r != nil && responseCategory(r.Response) == CategorySoftDecline
+}
+
+func (r *AuthorizationResponse) HardDecline() bool {
+	return r != nil && responseCategory(r.Response) == CategoryHardDecline
+}
+
+func (r *AuthorizationResponse) Retryable() bool {
+	return r != nil && responseRetryable(r.Response)
+}
+
+func (r *AuthReversalResponse) Approved() bool {
+	return r != nil && responseCategory(r.Response) == CategoryApproved
+}
+
+func (r *AuthReversalResponse) SoftDecline() bool {
+	return r != nil && responseCategory(r.Response) == CategorySoftDecline
+}
+
+func (r *AuthReversalResponse) HardDecline() bool {
+	return r != nil && responseCategory(r.Response) == CategoryHardDecline
+}
+
+func (r *AuthReversalResponse) Retryable() bool {
+	return r != nil && responseRetryable(r.Response)
+}
+
+func (r *CancelSubscriptionResponse) Approved() bool {
+	return r != nil && responseCategory(r.Response) == CategoryApproved
+}
+
+func (r *CancelSubscriptionResponse) SoftDecline() bool {
+	return r != nil && responseCategory(r.Response) == CategorySoftDecline
+}
+
+func (r *CancelSubscriptionResponse) HardDecline() bool {
+	return r != nil && responseCategory(r.Response) == CategoryHardDecline
+}
+
+func (r *CancelSubscriptionResponse) Retryable() bool {
+	return r != nil && responseRetryable(r.Response)
+}
+
+func (r *CaptureResponse) Approved() bool {
+	return r != nil && responseCategory(r.Response) == CategoryApproved
+}
+
+func (r *CaptureResponse) SoftDecline() bool {
+	return r != nil && responseCategory(r.Response) == CategorySoftDecline
+}
+
+func (r *CaptureResponse) HardDecline() bool {
+	return r != nil && responseCategory(r.Response) == CategoryHardDecline
+}
+
+func (r *CaptureResponse) Retryable() bool {
+	return r != nil && responseRetryable(r.Response)
+}
+
+func (r *CaptureGivenAuthResponse) Approved() bool {
+	return r != nil && responseCategory(r.Response) == CategoryApproved
+}
+
+func (r *CaptureGivenAuthResponse) SoftDecline() bool {
+	return r != nil && responseCategory(r.Response) == CategorySoftDecline
+}
+
+func (r *CaptureGivenAuthResponse) HardDecline() bool {
+	return r != nil && responseCategory(r.Response) == CategoryHardDecline
+}
+
+func (r *CaptureGivenAuthResponse) Retryable() bool {
+	return r != nil && responseRetryable(r.Response)
+}
+
+func (r *CreatePlanResponse) Approved() bool {
+	return r != nil && responseCategory(r.Response) == CategoryApproved
+}
+
+func (r *CreatePlanResponse) SoftDecline() bool {
+	return r != nil && responseCategory(r.Response) == CategorySoftDecline
+}
+
+func (r *CreatePlanResponse) HardDecline() bool {
+	return r != nil && responseCategory(r.Response) == CategoryHardDecline
+}
+
+func (r *CreatePlanResponse) Retryable() bool {
+	return r != nil && responseRetryable(r.Response)
+}
+
+func (r *CreditResponse) Approved() bool {
+	return r != nil && responseCategory(r.Response) == CategoryApproved
+}
+
+func (r *CreditResponse) SoftDecline() bool {
+	return r != nil && responseCategory(r.Response) == CategorySoftDecline
+}
+
+func (r *CreditResponse) HardDecline() bool {
+	return r != nil && responseCategory(r.Response) == CategoryHardDecline
+}
+
+func (r *CreditResponse) Retryable() bool {
+	return r != nil && responseRetryable(r.Response)
+}
+
+func (r *EcheckCreditResponse) Approved() bool {
+	return r != nil && responseCategory(r.Response) == CategoryApproved
+}
+
+func (r *EcheckCreditResponse) SoftDecline() bool {
+	return r != nil && responseCategory(r.Response) == CategorySoftDecline
+}
+
+func (r *EcheckCreditResponse) HardDecline() bool {
+	return r != nil && responseCategory(r.Response) == CategoryHardDecline
+}
+
+func (r *EcheckCreditResponse) Retryable() bool {
+	return r != nil && responseRetryable(r.Response)
+}
+
+func (r *EcheckSaleResponse) Approved() bool {
+	return r != nil && responseCategory(r.Response) == CategoryApproved
+}
+
+func (r *EcheckSaleResponse) SoftDecline() bool {
+	return r != nil && responseCategory(r.Response) == CategorySoftDecline
+}
+
+func (r *EcheckSaleResponse) HardDecline() bool {
+	return r != nil && responseCategory(r.Response) == CategoryHardDecline
+}
+
+func (r *EcheckSaleResponse) Retryable() bool {
+	return r != nil && responseRetryable(r.Response)
+}
+
+func (r *EcheckVoidResponse) Approved() bool {
+	return r != nil && responseCategory(r.Response) == CategoryApproved
+}
+
+func (r *EcheckVoidResponse) SoftDecline() bool {
+	return r != nil && responseCategory(r.Response) == CategorySoftDecline
+}
+
+func (r *EcheckVoidResponse) HardDecline() bool {
+	return r != nil && responseCategory(r.Response) == CategoryHardDecline
+}
+
+func (r *EcheckVoidResponse) Retryable() bool {
+	return r != nil && responseRetryable(r.Response)
+}
+
+func (r *ForceCaptureResponse) Approved() bool {
+	return r != nil && responseCategory(r.Response) == CategoryApproved
+}
+
+func (r *ForceCaptureResponse) SoftDecline() bool {
+	return r != nil && responseCategory(r.Response) == CategorySoftDecline
+}
+
+func (r *ForceCaptureResponse) HardDecline() bool {
+	return r != nil && responseCategory(r.Response) == CategoryHardDecline
+}
+
+func (r *ForceCaptureResponse) Retryable() bool {
+	return r != nil && responseRetryable(r.Response)
+}
+
+func (r *QueryTransactionResponse) Approved() bool {
+	return r != nil && responseCategory(r.Response) == CategoryApproved
+}
+
+func (r *QueryTransactionResponse) SoftDecline() bool {
+	return r != nil && responseCategory(r.Response) == CategorySoftDecline
+}
+
+func (r *QueryTransactionResponse) HardDecline() bool {
+	return r != nil && responseCategory(r.Response) == CategoryHardDecline
+}
+
+func (r *QueryTransactionResponse) Retryable() bool {
+	return r != nil && responseRetryable(r.Response)
+}
+
+func (r *QueryTransactionUnavailableResponse) Approved() bool {
+	return r != nil && responseCategory(r.Response) == CategoryApproved
+}
+
+func (r *QueryTransactionUnavailableResponse) SoftDecline() bool {
+	return r != nil && responseCategory(r.Response) == CategorySoftDecline
+}
+
+func (r *QueryTransactionUnavailableResponse) HardDecline() bool {
+	return r != nil && responseCategory(r.Response) == CategoryHardDecline
+}
+
+func (r *QueryTransactionUnavailableResponse) Retryable() bool {
+	return r != nil && responseRetryable(r.Response)
+}
+
+func (r *RegisterTokenResponse) Approved() bool {
+	return r != nil && responseCategory(r.Response) == CategoryApproved
+}
+
+func (r *RegisterTokenResponse) SoftDecline() bool {
+	return r != nil && responseCategory(r.Response) == CategorySoftDecline
+}
+
+func (r *RegisterTokenResponse) HardDecline() bool {
+	return r != nil && responseCategory(r.Response) == CategoryHardDecline
+}
+
+func (r *RegisterTokenResponse) Retryable() bool {
+	return r != nil && responseRetryable(r.Response)
+}
+
+func (r *SaleResponse) Approved() bool {
+	return r != nil && responseCategory(r.Response) == CategoryApproved
+}
+
+func (r *SaleResponse) SoftDecline() bool {
+	return r != nil && responseCategory(r.Response) == CategorySoftDecline
+}
+
+func (r *SaleResponse) HardDecline() bool {
+	return r != nil && responseCategory(r.Response) == CategoryHardDecline
+}
+
+func (r *SaleResponse) Retryable() bool {
+	return r != nil && responseRetryable(r.Response)
+}
+
+func (r *UpdatePlanResponse) Approved() bool {
+	return r != nil && responseCategory(r.Response) == CategoryApproved
+}
+
+func (r *UpdatePlanResponse) SoftDecline() bool {
+	return r != nil && responseCategory(r.Response) == CategorySoftDecline
+}
+
+func (r *UpdatePlanResponse) HardDecline() bool {
+	return r != nil && responseCategory(r.Response) == CategoryHardDecline
+}
+
+func (r *UpdatePlanResponse) Retryable() bool {
+	return r != nil && responseRetryable(r.Response)
+}
+
+func (r *UpdateSubscriptionResponse) Approved() bool {
+	return r != nil && responseCategory(r.Response) == CategoryApproved
+}
+
+func (r *UpdateSubscriptionResponse) SoftDecline() bool {
+	return r != nil && responseCategory(r.Response) == CategorySoftDecline
+}
+
+func (r *UpdateSubscriptionResponse) HardDecline() bool {
+	return r != nil && responseCategory(r.Response) == CategoryHardDecline
+}
+
+func (r *UpdateSubscriptionResponse) Retryable() bool {
+	return r != nil && responseRetryable(r.Response)
+}
+
+func (r *VoidResponse) Approved() bool {
+	return r != nil && responseCategory(r.Response) == CategoryApproved
+}
+
+func (r *VoidResponse) SoftDecline() bool {
+	return r != nil && responseCategory(r.Response) == CategorySoftDecline
+}
+
+func (r *VoidResponse) HardDecline() bool {
+	return r != nil && responseCategory(r.Response) == CategoryHardDecline
+}
+
+func (r *VoidResponse) Retryable() bool {
+	return r != nil && responseRetryable(r.Response)
+}
+
+// Found reports whether the query found the original transaction with its
+// response available (150). Approved is always false for a query response.
+func (r *QueryTransactionResponse) Found() bool {
+	return r != nil && r.Response == queryTransactionFound
+}
+
+// Pending reports whether the query found the original transaction but its
+// response is not yet available (152), in which case it should be queried
+// again later.
+func (r *QueryTransactionResponse) Pending() bool {
+	return r != nil && r.Response == queryTransactionPending
+}
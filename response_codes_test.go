@@ -0,0 +1,60 @@
+package worldpay
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLookupResponseCode(t *testing.T) {
+	rc, ok := LookupResponseCode("101")
+	assert.True(t, ok)
+	assert.Equal(t, "Issuer Unavailable", rc.Message)
+	assert.Equal(t, CategorySoftDecline, rc.Category)
+	assert.True(t, rc.Retryable)
+
+	rc, ok = LookupResponseCode("999")
+	assert.False(t, ok)
+	assert.Equal(t, CategoryUnknown, rc.Category)
+	assert.Equal(t, "unknown", rc.Category.String())
+}
+
+func TestResponseClassification(t *testing.T) {
+	approved := &SaleResponse{Response: "000"}
+	assert.True(t, approved.Approved())
+	assert.False(t, approved.Retryable())
+
+	issuerUnavailable := &AuthorizationResponse{Response: "101"}
+	assert.False(t, issuerUnavailable.Approved())
+	assert.True(t, issuerUnavailable.SoftDecline())
+	assert.True(t, issuerUnavailable.Retryable())
+
+	expired := &SaleResponse{Response: "305"}
+	assert.True(t, expired.HardDecline())
+	assert.False(t, expired.Retryable())
+
+	var missing *SaleResponse
+	assert.False(t, missing.Approved())
+
+	var res LitleOnlineResponse
+	assert.False(t, res.SaleResponse.Approved())
+}
+
+func TestQueryTransactionResponse(t *testing.T) {
+	found := &QueryTransactionResponse{Response: "150"}
+	assert.True(t, found.Found())
+	assert.False(t, found.Approved())
+	assert.False(t, found.Pending())
+
+	pending := &QueryTransactionResponse{Response: "152"}
+	assert.True(t, pending.Pending())
+	assert.False(t, pending.Retryable())
+	assert.False(t, pending.Found())
+
+	notFound := &QueryTransactionResponse{Response: "151"}
+	assert.False(t, notFound.Found())
+	assert.False(t, notFound.HardDecline())
+
+	rc, _ := LookupResponseCode("150")
+	assert.Equal(t, "query", rc.Category.String())
+}
@@ -0,0 +1,135 @@
+package worldpay
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"net"
+	"net/http"
+	"time"
+)
+
+type (
+	// RetryPolicy controls how executeRequest retries failed transactions.
+	RetryPolicy struct {
+		// MaxAttempts is the total number of attempts, including the first.
+		MaxAttempts int
+		// InitialBackoff is the wait before the second attempt. Each further
+		// wait is doubled, up to MaxBackoff.
+		InitialBackoff time.Duration
+		MaxBackoff     time.Duration
+		// Jitter is the fraction of each wait, between 0 and 1, that is
+		// randomised to spread out retries from concurrent callers.
+		Jitter float64
+		// Classifier decides whether an attempt should be retried. When nil,
+		// DefaultRetryClassifier is used.
+		Classifier func(attempt RetryAttempt) bool
+	}
+
+	// RetryAttempt describes the outcome of a single attempt.
+	RetryAttempt struct {
+		Attempt  int
+		Payload  interface{}
+		Response *LitleOnlineResponse
+		Err      error
+	}
+)
+
+func DefaultRetryPolicy() *RetryPolicy {
+	return &RetryPolicy{
+		MaxAttempts:    3,
+		InitialBackoff: 250 * time.Millisecond,
+		MaxBackoff:     5 * time.Second,
+		Jitter:         0.2,
+	}
+}
+
+func (c *Client) SetRetryPolicy(policy *RetryPolicy) {
+	c.Retry = policy
+}
+
+// DefaultRetryClassifier retries failures that cannot have reached the
+// gateway. Failures after the request may have been processed, such as
+// timeouts and 5xx responses, are only retried when the transaction carries
+// an Id, which the gateway uses to detect duplicates. Transaction responses
+// are never retried, even retryable declines such as 101 Issuer Unavailable:
+// a retry resends the same Id, which the gateway answers with the original
+// response.
+func DefaultRetryClassifier(attempt RetryAttempt) bool {
+	if attempt.Err == nil {
+		return false
+	}
+
+	var (
+		transportErr *TransportError
+		statusErr    *HTTPStatusError
+	)
+	switch {
+	case errors.As(attempt.Err, &transportErr):
+		if errors.Is(attempt.Err, context.Canceled) {
+			return false
+		}
+		if isDialError(transportErr.Err) {
+			return true
+		}
+		return describeTransaction(attempt.Payload).Id != ""
+	case errors.As(attempt.Err, &statusErr):
+		return statusErr.StatusCode >= 500 && describeTransaction(attempt.Payload).Id != ""
+	}
+
+	return false
+}
+
+func (p *RetryPolicy) shouldRetry(attempt RetryAttempt) bool {
+	if attempt.Attempt >= p.MaxAttempts {
+		return false
+	}
+	if p.Classifier != nil {
+		return p.Classifier(attempt)
+	}
+	return DefaultRetryClassifier(attempt)
+}
+
+func (p *RetryPolicy) backoff(attempt int) time.Duration {
+	d := p.InitialBackoff
+	for i := 1; i < attempt && d < p.MaxBackoff; i++ {
+		d *= 2
+	}
+	if p.MaxBackoff > 0 && d > p.MaxBackoff {
+		d = p.MaxBackoff
+	}
+	if p.Jitter > 0 {
+		d -= time.Duration(rand.Float64() * p.Jitter * float64(d))
+	}
+	return d
+}
+
+func (p *RetryPolicy) wait(ctx context.Context, attempt int) error {
+	timer := time.NewTimer(p.backoff(attempt))
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// rewindRequest returns a copy of req with a fresh body for another attempt.
+func rewindRequest(ctx context.Context, req *http.Request) (*http.Request, error) {
+	r := req.Clone(ctx)
+	if req.GetBody != nil {
+		body, err := req.GetBody()
+		if err != nil {
+			return nil, err
+		}
+		r.Body = body
+	}
+	return r, nil
+}
+
+func isDialError(err error) bool {
+	var opErr *net.OpError
+	return errors.As(err, &opErr) && opErr.Op == "dial"
+}
@@ -0,0 +1,122 @@
+package worldpay
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func testRetryPolicy() *RetryPolicy {
+	return &RetryPolicy{
+		MaxAttempts:    3,
+		InitialBackoff: time.Millisecond,
+		MaxBackoff:     5 * time.Millisecond,
+	}
+}
+
+func TestRetryResponseCode(t *testing.T) {
+	var hits int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&hits, 1)
+		w.Write([]byte(`<litleOnlineResponse version="11.4" xmlns="http://www.litle.com/schema" response="0" message="Valid Format">` +
+			`<saleResponse id="1"><response>101</response><message>Issuer Unavailable</message></saleResponse>` +
+			`</litleOnlineResponse>`))
+	}))
+	defer server.Close()
+
+	c, _ := NewClient(login, password, server.URL)
+	c.SetRetryPolicy(testRetryPolicy())
+
+	// A resend with the same Id would only be answered with the decline.
+	res, err := c.Sale(context.Background(), merchantId, &Sale{Id: "1", Amount: 1000})
+	assert.Nil(t, err)
+	assert.Equal(t, int32(1), atomic.LoadInt32(&hits))
+	assert.Equal(t, "101", res.SaleResponse.Response)
+	assert.True(t, res.SaleResponse.Retryable())
+}
+
+func TestRetryTransportError(t *testing.T) {
+	var hits int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&hits, 1)
+		// Drop the connection after the request has been received, leaving
+		// its outcome unknown.
+		conn, _, _ := w.(http.Hijacker).Hijack()
+		conn.Close()
+	}))
+	defer server.Close()
+
+	c, _ := NewClient(login, password, server.URL)
+	c.SetRetryPolicy(testRetryPolicy())
+
+	t.Run("without Id", func(t *testing.T) {
+		atomic.StoreInt32(&hits, 0)
+		_, err := c.Sale(context.Background(), merchantId, &Sale{Amount: 1000})
+		assert.IsType(t, &TransportError{}, err)
+		assert.Equal(t, int32(1), atomic.LoadInt32(&hits))
+	})
+
+	t.Run("with Id", func(t *testing.T) {
+		atomic.StoreInt32(&hits, 0)
+		_, err := c.Sale(context.Background(), merchantId, &Sale{Id: "1", Amount: 1000})
+		assert.IsType(t, &TransportError{}, err)
+		assert.Equal(t, int32(3), atomic.LoadInt32(&hits))
+	})
+}
+
+func TestRetryClassifier(t *testing.T) {
+	var hits int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&hits, 1)
+		w.WriteHeader(http.StatusBadRequest)
+	}))
+	defer server.Close()
+
+	policy := testRetryPolicy()
+	policy.Classifier = func(attempt RetryAttempt) bool { return true }
+
+	c, _ := NewClient(login, password, server.URL)
+	c.SetRetryPolicy(policy)
+
+	_, err := c.Void(context.Background(), merchantId, &Void{LitleTxnId: "1"})
+	assert.IsType(t, &HTTPStatusError{}, err)
+	assert.Equal(t, int32(3), atomic.LoadInt32(&hits))
+}
+
+func TestRetryRewindError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	c, _ := NewClient(login, password, server.URL)
+	c.SetRetryPolicy(testRetryPolicy())
+
+	payload := &Sale{Id: "1", Amount: 1000}
+	req, _ := c.NewRequest(context.Background(), merchantId, payload)
+	req.GetBody = func() (io.ReadCloser, error) {
+		return nil, errors.New("body cannot be rewound")
+	}
+
+	// The request cannot be sent again, so the first attempt's error stands.
+	_, err := c.executeRequest(context.Background(), req, payload)
+	var statusErr *HTTPStatusError
+	assert.ErrorAs(t, err, &statusErr)
+	assert.Equal(t, http.StatusServiceUnavailable, statusErr.StatusCode)
+}
+
+func TestRetryPolicyBackoff(t *testing.T) {
+	policy := &RetryPolicy{InitialBackoff: 100 * time.Millisecond, MaxBackoff: time.Second}
+
+	assert.Equal(t, 100*time.Millisecond, policy.backoff(1))
+	assert.Equal(t, 200*time.Millisecond, policy.backoff(2))
+	assert.Equal(t, 400*time.Millisecond, policy.backoff(3))
+	assert.Equal(t, time.Second, policy.backoff(10))
+}
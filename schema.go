@@ -0,0 +1,136 @@
+package worldpay
+
+import (
+	"bytes"
+	"encoding/xml"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// SchemaVersion is the cnpAPI schema version requests are sent with. From
+// 12.0 the litle-prefixed elements, such as litleOnlineRequest and litleTxnId,
+// are renamed to cnpOnlineRequest and cnpTxnId, and the namespace changes.
+// The Go types keep their Litle names under either version.
+type SchemaVersion string
+
+const (
+	SchemaVersion11 SchemaVersion = "11.4"
+	SchemaVersion12 SchemaVersion = "12.0"
+
+	litleNamespace = "http://www.litle.com/schema"
+	cnpNamespace   = "http://www.vantivcnp.com/schema"
+)
+
+// cnp reports whether the version uses the cnp-prefixed element names.
+func (v SchemaVersion) cnp() bool {
+	major, _, _ := strings.Cut(string(v), ".")
+	n, err := strconv.Atoi(major)
+	return err == nil && n >= 12
+}
+
+func (v SchemaVersion) namespace() string {
+	if v.cnp() {
+		return cnpNamespace
+	}
+	return litleNamespace
+}
+
+func (c *Client) schemaVersion() SchemaVersion {
+	if c.Schema == "" {
+		return SchemaVersion11
+	}
+	return c.Schema
+}
+
+// cnpName renames a litle-prefixed element or attribute, e.g. litleTxnId to
+// cnpTxnId and origLitleTxnId to origCnpTxnId.
+func cnpName(name string) string {
+	if strings.HasPrefix(name, "litle") {
+		name = "cnp" + strings.TrimPrefix(name, "litle")
+	}
+	return strings.Replace(name, "Litle", "Cnp", 1)
+}
+
+// litleName reverses cnpName.
+func litleName(name string) string {
+	if strings.HasPrefix(name, "cnp") {
+		name = "litle" + strings.TrimPrefix(name, "cnp")
+	}
+	return strings.Replace(name, "Cnp", "Litle", 1)
+}
+
+// toCnp rewrites a marshaled request with the cnp-prefixed names used from
+// schema 12.0.
+func toCnp(data []byte) ([]byte, error) {
+	dec := xml.NewDecoder(bytes.NewReader(data))
+
+	var buf bytes.Buffer
+	enc := xml.NewEncoder(&buf)
+	for {
+		tok, err := dec.RawToken()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		if err := enc.EncodeToken(renameToken(tok, cnpName)); err != nil {
+			return nil, err
+		}
+	}
+	if err := enc.Flush(); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+// MarshalSchema encodes a request or response with the element names of the
+// given schema version.
+func MarshalSchema(v interface{}, version SchemaVersion) ([]byte, error) {
+	data, err := xml.MarshalIndent(v, "", "  ")
+	if err != nil || !version.cnp() {
+		return data, err
+	}
+	return toCnp(data)
+}
+
+// litleTokenReader renames cnp-prefixed elements to their litle equivalents,
+// so that both schema versions decode into the same types.
+type litleTokenReader struct {
+	dec *xml.Decoder
+}
+
+// NewSchemaDecoder returns a decoder that reads requests and responses of
+// either schema version into the package's types.
+func NewSchemaDecoder(r io.Reader) *xml.Decoder {
+	return xml.NewTokenDecoder(litleTokenReader{dec: xml.NewDecoder(r)})
+}
+
+func (r litleTokenReader) Token() (xml.Token, error) {
+	tok, err := r.dec.Token()
+	if err != nil {
+		return nil, err
+	}
+	return renameToken(tok, litleName), nil
+}
+
+func renameToken(tok xml.Token, rename func(string) string) xml.Token {
+	switch t := tok.(type) {
+	case xml.StartElement:
+		t = t.Copy()
+		t.Name.Local = rename(t.Name.Local)
+		for i, attr := range t.Attr {
+			if attr.Name.Space == "" && attr.Name.Local != "xmlns" {
+				t.Attr[i].Name.Local = rename(attr.Name.Local)
+			}
+		}
+		return t
+	case xml.EndElement:
+		t.Name.Local = rename(t.Name.Local)
+		return t
+	}
+	return tok
+}
@@ -0,0 +1,87 @@
+package worldpay
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSchemaVersion(t *testing.T) {
+	assert.False(t, SchemaVersion11.cnp())
+	assert.True(t, SchemaVersion12.cnp())
+	assert.True(t, SchemaVersion("12.8").cnp())
+	assert.False(t, SchemaVersion("").cnp())
+}
+
+func TestGetTransactionXmlSchemaVersion(t *testing.T) {
+	void := &Void{Id: "1", LitleTxnId: "345454444"}
+
+	c, _ := NewClient(login, password, apiBase)
+	res, _ := c.GetTransactionXml(merchantId, void)
+	assert.Contains(t, string(res), `<litleOnlineRequest version="11.4" xmlns="http://www.litle.com/schema" merchantId="100">`)
+	assert.Contains(t, string(res), "<litleTxnId>345454444</litleTxnId>")
+
+	c, _ = NewClient(login, password, apiBase, WithSchemaVersion(SchemaVersion12))
+	res, err := c.GetTransactionXml(merchantId, void)
+	assert.Nil(t, err)
+	assert.Contains(t, string(res), `<cnpOnlineRequest version="12.0" xmlns="http://www.vantivcnp.com/schema" merchantId="100">`)
+	assert.Contains(t, string(res), "<cnpTxnId>345454444</cnpTxnId>")
+	assert.Contains(t, string(res), "</cnpOnlineRequest>")
+	assert.NotContains(t, string(res), "litle")
+}
+
+func TestGetTransactionXmlProcessingType(t *testing.T) {
+	c, _ := NewClient(login, password, apiBase, WithSchemaVersion(SchemaVersion12))
+
+	res, _ := c.GetTransactionXml(merchantId, &Sale{Id: "1", OrderId: "1", Amount: 100, OrderSource: "ecommerce", ProcessingType: "initialCOF"})
+	assert.Contains(t, string(res), "<processingType>initialCOF</processingType>")
+
+	res, _ = c.GetTransactionXml(merchantId, &Sale{Id: "1", OrderId: "1", Amount: 100, OrderSource: "ecommerce"})
+	assert.NotContains(t, string(res), "processingType")
+}
+
+func TestSendCnpResponse(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`<cnpOnlineResponse version="12.0" xmlns="http://www.vantivcnp.com/schema" response="0" message="Valid Format">` +
+			`<saleResponse id="1"><cnpTxnId>82924701437133501</cnpTxnId><response>000</response><message>Approved</message>` +
+			`<tokenResponse><cnpToken>1111222233334444</cnpToken></tokenResponse></saleResponse></cnpOnlineResponse>`))
+	}))
+	defer server.Close()
+
+	c, _ := NewClient(login, password, server.URL, WithSchemaVersion(SchemaVersion12))
+
+	res, err := c.Sale(context.Background(), merchantId, &Sale{Id: "1"})
+	assert.Nil(t, err)
+	assert.Equal(t, "12.0", res.Version)
+	assert.Equal(t, "82924701437133501", res.SaleResponse.LitleTxnId)
+	assert.Equal(t, "1111222233334444", res.SaleResponse.TokenResponse.LitleToken)
+	assert.True(t, res.SaleResponse.Approved())
+}
+
+func TestBatchSchemaVersion(t *testing.T) {
+	c, _ := NewClient(login, password, apiBase, WithSchemaVersion(SchemaVersion12))
+
+	req, _ := c.NewRFRRequest(context.Background(), "82822223274065939")
+
+	var buf bytes.Buffer
+	_, err := req.WriteTo(&buf)
+	assert.Nil(t, err)
+	assert.Contains(t, buf.String(), `<cnpRequest version="12.0" xmlns="http://www.vantivcnp.com/schema" numBatchRequests="0">`)
+	assert.Contains(t, buf.String(), "<cnpSessionId>82822223274065939</cnpSessionId>")
+
+	r := NewBatchResponseReader(strings.NewReader(`<cnpResponse version="12.0" xmlns="http://www.vantivcnp.com/schema" response="0" message="Valid Format" cnpSessionId="82822223274065939">` +
+		`<batchResponse id="1" cnpBatchId="82822223274065940" merchantId="100">` +
+		`<saleResponse id="1"><cnpTxnId>82924701437133501</cnpTxnId><response>000</response></saleResponse>` +
+		`</batchResponse></cnpResponse>`))
+
+	v, err := r.Next()
+	assert.Nil(t, err)
+	assert.Equal(t, "82924701437133501", v.(*SaleResponse).LitleTxnId)
+	assert.Equal(t, "82822223274065939", r.Response().LitleSessionId)
+	assert.Equal(t, "82822223274065940", r.Batch().LitleBatchId)
+}
@@ -0,0 +1,115 @@
+package worldpay
+
+import (
+	"context"
+	"log/slog"
+	"time"
+)
+
+// merchantIdKey is the request context key holding the merchantId an online
+// request was resolved to.
+type merchantIdKey struct{}
+
+// transactionRecord is what is known about an online transaction once its
+// last attempt has completed.
+type transactionRecord struct {
+	payload    interface{}
+	merchantId string
+	response   *LitleOnlineResponse
+	status     int
+	attempts   int
+	latency    time.Duration
+	err        error
+}
+
+// logTransaction emits a single record for a transaction to the client's
+// StructuredLogger. Failed transactions are logged at error level, all others,
+// including declines, at info level.
+func (c *Client) logTransaction(ctx context.Context, r transactionRecord) {
+	if c.StructuredLogger == nil {
+		return
+	}
+
+	txn := describeTransaction(r.payload)
+	attrs := []slog.Attr{
+		slog.String("transaction", txn.Type),
+		slog.String("merchantId", r.merchantId),
+		slog.String("id", txn.Id),
+		slog.String("orderId", txn.OrderId),
+	}
+
+	// The response is only reported when one was decoded, not for transport
+	// and HTTP failures.
+	var txnRes TransactionResponse
+	if r.response != nil {
+		txnRes = r.response.TransactionResponse()
+	}
+	if txnRes != nil {
+		litleTxnId, code := transactionResult(txnRes)
+		attrs = append(attrs,
+			slog.String("litleTxnId", litleTxnId),
+			slog.String("response", code),
+		)
+	}
+	if r.status != 0 {
+		attrs = append(attrs, slog.Int("httpStatus", r.status))
+	}
+	attrs = append(attrs,
+		slog.Duration("latency", r.latency),
+		slog.Int("attempts", r.attempts),
+	)
+
+	level := slog.LevelInfo
+	if r.err != nil {
+		level = slog.LevelError
+		// Error messages may quote a response body, mask it as the
+		// request and response logs are.
+		attrs = append(attrs, slog.String("error", string(MaskSensitive([]byte(r.err.Error())))))
+	}
+
+	c.StructuredLogger.LogAttrs(ctx, level, "worldpay transaction", attrs...)
+}
+
+// transactionResult returns the litleTxnId and response code of a
+// transaction response.
+func transactionResult(txn TransactionResponse) (string, string) {
+	switch r := txn.(type) {
+	case *AuthorizationResponse:
+		return r.LitleTxnId, r.Response
+	case *AuthReversalResponse:
+		return r.LitleTxnId, r.Response
+	case *CancelSubscriptionResponse:
+		return r.LitleTxnId, r.Response
+	case *CaptureResponse:
+		return r.LitleTxnId, r.Response
+	case *CaptureGivenAuthResponse:
+		return r.LitleTxnId, r.Response
+	case *CreatePlanResponse:
+		return r.LitleTxnId, r.Response
+	case *CreditResponse:
+		return r.LitleTxnId, r.Response
+	case *EcheckCreditResponse:
+		return r.LitleTxnId, r.Response
+	case *EcheckSaleResponse:
+		return r.LitleTxnId, r.Response
+	case *EcheckVoidResponse:
+		return r.LitleTxnId, r.Response
+	case *ForceCaptureResponse:
+		return r.LitleTxnId, r.Response
+	case *QueryTransactionResponse:
+		return "", r.Response
+	case *QueryTransactionUnavailableResponse:
+		return r.LitleTxnId, r.Response
+	case *RegisterTokenResponse:
+		return r.LitleTxnId, r.Response
+	case *SaleResponse:
+		return r.LitleTxnId, r.Response
+	case *UpdatePlanResponse:
+		return r.LitleTxnId, r.Response
+	case *UpdateSubscriptionResponse:
+		return r.LitleTxnId, r.Response
+	case *VoidResponse:
+		return r.LitleTxnId, r.Response
+	}
+	return "", ""
+}
@@ -0,0 +1,171 @@
+package worldpay
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func decodeRecords(t *testing.T, buf *bytes.Buffer) []map[string]interface{} {
+	var records []map[string]interface{}
+	for _, line := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+		if line == "" {
+			continue
+		}
+		var record map[string]interface{}
+		if err := json.Unmarshal([]byte(line), &record); err != nil {
+			t.Fatal(err)
+		}
+		records = append(records, record)
+	}
+	return records
+}
+
+func TestStructuredLogger(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`<litleOnlineResponse version="11.4" xmlns="http://www.litle.com/schema" response="0" message="Valid Format">` +
+			`<saleResponse id="1"><litleTxnId>82924701437133501</litleTxnId><orderId>order-1</orderId><response>000</response><message>Approved</message></saleResponse>` +
+			`</litleOnlineResponse>`))
+	}))
+	defer server.Close()
+
+	var buf bytes.Buffer
+	c, _ := NewClient(login, password, server.URL, WithStructuredLogger(slog.New(slog.NewJSONHandler(&buf, nil))))
+
+	_, err := c.Sale(context.Background(), merchantId, &Sale{
+		Id:      "1",
+		OrderId: "order-1",
+		Amount:  1000,
+		Card:    Card{Type: "VI", Number: "4457010000000009", ExpDate: "0121"},
+	})
+	assert.Nil(t, err)
+
+	records := decodeRecords(t, &buf)
+	if assert.Len(t, records, 1) {
+		record := records[0]
+		assert.Equal(t, "INFO", record["level"])
+		assert.Equal(t, "worldpay transaction", record["msg"])
+		assert.Equal(t, "sale", record["transaction"])
+		assert.Equal(t, merchantId, record["merchantId"])
+		assert.Equal(t, "1", record["id"])
+		assert.Equal(t, "order-1", record["orderId"])
+		assert.Equal(t, "82924701437133501", record["litleTxnId"])
+		assert.Equal(t, "000", record["response"])
+		assert.Equal(t, float64(http.StatusOK), record["httpStatus"])
+		assert.Equal(t, float64(1), record["attempts"])
+		assert.Contains(t, record, "latency")
+		assert.NotContains(t, record, "error")
+	}
+	assert.NotContains(t, buf.String(), "4457010000000009")
+}
+
+func TestStructuredLoggerDefaultMerchant(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`<litleOnlineResponse version="11.4" xmlns="http://www.litle.com/schema" response="0" message="Valid Format">` +
+			`<voidResponse id="2"><litleTxnId>82924701437133502</litleTxnId><response>360</response><message>No transaction found with specified litleTxnId</message></voidResponse>` +
+			`</litleOnlineResponse>`))
+	}))
+	defer server.Close()
+
+	var buf bytes.Buffer
+	c, _ := NewClient(login, password, server.URL,
+		WithDefaultMerchant("default-merchant"),
+		WithStructuredLogger(slog.New(slog.NewJSONHandler(&buf, nil))),
+	)
+
+	_, err := c.Void(context.Background(), "", &Void{Id: "2", LitleTxnId: "82924701437133500"})
+	assert.Nil(t, err)
+
+	records := decodeRecords(t, &buf)
+	if assert.Len(t, records, 1) {
+		// Declines are not errors.
+		assert.Equal(t, "INFO", records[0]["level"])
+		assert.Equal(t, "void", records[0]["transaction"])
+		assert.Equal(t, "default-merchant", records[0]["merchantId"])
+		assert.Equal(t, "", records[0]["orderId"])
+		assert.Equal(t, "360", records[0]["response"])
+	}
+}
+
+func TestStructuredLoggerError(t *testing.T) {
+	var hits int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&hits, 1)
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	var buf bytes.Buffer
+	c, _ := NewClient(login, password, server.URL, WithStructuredLogger(slog.New(slog.NewJSONHandler(&buf, nil))))
+	c.SetRetryPolicy(testRetryPolicy())
+
+	_, err := c.Sale(context.Background(), merchantId, &Sale{Id: "3", OrderId: "order-3", Amount: 1000})
+	assert.NotNil(t, err)
+
+	// One record is emitted for the transaction, however many attempts it took.
+	records := decodeRecords(t, &buf)
+	if assert.Len(t, records, 1) {
+		record := records[0]
+		assert.Equal(t, "ERROR", record["level"])
+		assert.Equal(t, "sale", record["transaction"])
+		assert.Equal(t, "3", record["id"])
+		assert.Equal(t, float64(http.StatusServiceUnavailable), record["httpStatus"])
+		assert.Equal(t, float64(atomic.LoadInt32(&hits)), record["attempts"])
+		assert.Equal(t, err.Error(), record["error"])
+		assert.NotContains(t, record, "litleTxnId")
+		assert.NotContains(t, record, "response")
+	}
+}
+
+func TestStructuredLoggerTransportError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	server.Close()
+
+	var buf bytes.Buffer
+	c, _ := NewClient(login, password, server.URL, WithStructuredLogger(slog.New(slog.NewJSONHandler(&buf, nil))))
+
+	_, err := c.Capture(context.Background(), merchantId, &Capture{Id: "4", LitleTxnId: "82924701437133501"})
+	assert.NotNil(t, err)
+
+	records := decodeRecords(t, &buf)
+	if assert.Len(t, records, 1) {
+		assert.Equal(t, "ERROR", records[0]["level"])
+		assert.Equal(t, "capture", records[0]["transaction"])
+		assert.NotContains(t, records[0], "httpStatus")
+		assert.NotContains(t, records[0], "litleTxnId")
+		assert.NotContains(t, records[0], "response")
+	}
+}
+
+func TestStructuredLoggerDecodeErrorMasking(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Truncated response, which fails to decode.
+		w.Write([]byte(`<litleOnlineResponse version="11.4" xmlns="http://www.litle.com/schema" response="0" message="Valid Format">` +
+			`<registerTokenResponse id="5"><litleTxnId>82924701437133505</litleTxnId><number>4457000300000007</number><litleToken>1111000011110007</litleToken>`))
+	}))
+	defer server.Close()
+
+	var buf bytes.Buffer
+	c, _ := NewClient(login, password, server.URL, WithStructuredLogger(slog.New(slog.NewJSONHandler(&buf, nil))))
+
+	_, err := c.Sale(context.Background(), merchantId, &Sale{Id: "5", OrderId: "order-5", Amount: 1000})
+	var decodeErr *DecodeError
+	assert.True(t, errors.As(err, &decodeErr))
+
+	records := decodeRecords(t, &buf)
+	if assert.Len(t, records, 1) {
+		assert.Equal(t, "ERROR", records[0]["level"])
+		assert.Contains(t, records[0]["error"], "0007")
+	}
+	assert.NotContains(t, buf.String(), "4457000300000007")
+	assert.NotContains(t, buf.String(), "1111000011110007")
+}
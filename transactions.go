@@ -3,6 +3,7 @@ package worldpay
 import (
 	"context"
 	"net/http"
+	"time"
 )
 
 func (c *Client) Authorization(ctx context.Context, merchantId string, auth *Authorization) (*LitleOnlineResponse, error) {
@@ -10,7 +11,27 @@ func (c *Client) Authorization(ctx context.Context, merchantId string, auth *Aut
 	if err != nil {
 		return nil, err
 	}
-	return c.executeRequest(ctx, req)
+	res, err := c.executeRequest(ctx, req, auth)
+	if c.shouldAutoReverse(auth, err) {
+		go c.autoReverse(ctx, merchantId, auth, err)
+	}
+	return res, err
+}
+
+func (c *Client) AuthReversal(ctx context.Context, merchantId string, authReversal *AuthReversal) (*LitleOnlineResponse, error) {
+	req, err := c.NewRequest(ctx, merchantId, authReversal)
+	if err != nil {
+		return nil, err
+	}
+	return c.executeRequest(ctx, req, authReversal)
+}
+
+func (c *Client) CancelSubscription(ctx context.Context, merchantId string, cancelSubscription *CancelSubscription) (*LitleOnlineResponse, error) {
+	req, err := c.NewRequest(ctx, merchantId, cancelSubscription)
+	if err != nil {
+		return nil, err
+	}
+	return c.executeRequest(ctx, req, cancelSubscription)
 }
 
 func (c *Client) Capture(ctx context.Context, merchantId string, capture *Capture) (*LitleOnlineResponse, error) {
@@ -18,7 +39,23 @@ func (c *Client) Capture(ctx context.Context, merchantId string, capture *Captur
 	if err != nil {
 		return nil, err
 	}
-	return c.executeRequest(ctx, req)
+	return c.executeRequest(ctx, req, capture)
+}
+
+func (c *Client) CaptureGivenAuth(ctx context.Context, merchantId string, captureGivenAuth *CaptureGivenAuth) (*LitleOnlineResponse, error) {
+	req, err := c.NewRequest(ctx, merchantId, captureGivenAuth)
+	if err != nil {
+		return nil, err
+	}
+	return c.executeRequest(ctx, req, captureGivenAuth)
+}
+
+func (c *Client) CreatePlan(ctx context.Context, merchantId string, createPlan *CreatePlan) (*LitleOnlineResponse, error) {
+	req, err := c.NewRequest(ctx, merchantId, createPlan)
+	if err != nil {
+		return nil, err
+	}
+	return c.executeRequest(ctx, req, createPlan)
 }
 
 func (c *Client) Credit(ctx context.Context, merchantId string, credit *Credit) (*LitleOnlineResponse, error) {
@@ -26,7 +63,7 @@ func (c *Client) Credit(ctx context.Context, merchantId string, credit *Credit)
 	if err != nil {
 		return nil, err
 	}
-	return c.executeRequest(ctx, req)
+	return c.executeRequest(ctx, req, credit)
 }
 
 func (c *Client) EcheckCredit(ctx context.Context, merchantId string, echeckCredit *EcheckCredit) (*LitleOnlineResponse, error) {
@@ -34,7 +71,7 @@ func (c *Client) EcheckCredit(ctx context.Context, merchantId string, echeckCred
 	if err != nil {
 		return nil, err
 	}
-	return c.executeRequest(ctx, req)
+	return c.executeRequest(ctx, req, echeckCredit)
 }
 
 func (c *Client) EcheckSale(ctx context.Context, merchantId string, echeckSale *EcheckSale) (*LitleOnlineResponse, error) {
@@ -42,7 +79,7 @@ func (c *Client) EcheckSale(ctx context.Context, merchantId string, echeckSale *
 	if err != nil {
 		return nil, err
 	}
-	return c.executeRequest(ctx, req)
+	return c.executeRequest(ctx, req, echeckSale)
 }
 
 func (c *Client) EcheckVoid(ctx context.Context, merchantId string, echeckVoid *EcheckVoid) (*LitleOnlineResponse, error) {
@@ -50,7 +87,31 @@ func (c *Client) EcheckVoid(ctx context.Context, merchantId string, echeckVoid *
 	if err != nil {
 		return nil, err
 	}
-	return c.executeRequest(ctx, req)
+	return c.executeRequest(ctx, req, echeckVoid)
+}
+
+func (c *Client) ForceCapture(ctx context.Context, merchantId string, forceCapture *ForceCapture) (*LitleOnlineResponse, error) {
+	req, err := c.NewRequest(ctx, merchantId, forceCapture)
+	if err != nil {
+		return nil, err
+	}
+	return c.executeRequest(ctx, req, forceCapture)
+}
+
+func (c *Client) QueryTransaction(ctx context.Context, merchantId string, queryTransaction *QueryTransaction) (*LitleOnlineResponse, error) {
+	req, err := c.NewRequest(ctx, merchantId, queryTransaction)
+	if err != nil {
+		return nil, err
+	}
+	return c.executeRequest(ctx, req, queryTransaction)
+}
+
+func (c *Client) RegisterToken(ctx context.Context, merchantId string, registerToken *RegisterTokenRequest) (*LitleOnlineResponse, error) {
+	req, err := c.NewRequest(ctx, merchantId, registerToken)
+	if err != nil {
+		return nil, err
+	}
+	return c.executeRequest(ctx, req, registerToken)
 }
 
 func (c *Client) Sale(ctx context.Context, merchantId string, sale *Sale) (*LitleOnlineResponse, error) {
@@ -58,7 +119,27 @@ func (c *Client) Sale(ctx context.Context, merchantId string, sale *Sale) (*Litl
 	if err != nil {
 		return nil, err
 	}
-	return c.executeRequest(ctx, req)
+	res, err := c.executeRequest(ctx, req, sale)
+	if c.shouldAutoReverse(sale, err) {
+		go c.autoReverse(ctx, merchantId, sale, err)
+	}
+	return res, err
+}
+
+func (c *Client) UpdatePlan(ctx context.Context, merchantId string, updatePlan *UpdatePlan) (*LitleOnlineResponse, error) {
+	req, err := c.NewRequest(ctx, merchantId, updatePlan)
+	if err != nil {
+		return nil, err
+	}
+	return c.executeRequest(ctx, req, updatePlan)
+}
+
+func (c *Client) UpdateSubscription(ctx context.Context, merchantId string, updateSubscription *UpdateSubscription) (*LitleOnlineResponse, error) {
+	req, err := c.NewRequest(ctx, merchantId, updateSubscription)
+	if err != nil {
+		return nil, err
+	}
+	return c.executeRequest(ctx, req, updateSubscription)
 }
 
 func (c *Client) Void(ctx context.Context, merchantId string, void *Void) (*LitleOnlineResponse, error) {
@@ -66,11 +147,107 @@ func (c *Client) Void(ctx context.Context, merchantId string, void *Void) (*Litl
 	if err != nil {
 		return nil, err
 	}
-	return c.executeRequest(ctx, req)
+	return c.executeRequest(ctx, req, void)
 }
 
-func (c *Client) executeRequest(ctx context.Context, req *http.Request) (*LitleOnlineResponse, error) {
-	response := &LitleOnlineResponse{}
-	err := c.Send(req, response)
+func (c *Client) executeRequest(ctx context.Context, req *http.Request, payload interface{}) (*LitleOnlineResponse, error) {
+	var (
+		start    = time.Now()
+		response *LitleOnlineResponse
+		status   int
+		err      error
+		attempt  int
+	)
+
+	// Each attempt sends a clone of the request made with the caller's
+	// context, so the merchant is read from the original first.
+	merchantId, _ := req.Context().Value(merchantIdKey{}).(string)
+
+	for attempt = 1; ; attempt++ {
+		response = &LitleOnlineResponse{}
+		status, err = c.send(req, response)
+
+		if c.Retry == nil || !c.Retry.shouldRetry(RetryAttempt{
+			Attempt:  attempt,
+			Payload:  payload,
+			Response: response,
+			Err:      err,
+		}) {
+			break
+		}
+
+		if c.Retry.wait(ctx, attempt) != nil {
+			break
+		}
+
+		// A request that cannot be rewound ends with the last attempt's
+		// error, which is the outcome the caller needs to act on.
+		next, rewindErr := rewindRequest(ctx, req)
+		if rewindErr != nil {
+			break
+		}
+		req = next
+	}
+
+	c.logTransaction(ctx, transactionRecord{
+		payload:    payload,
+		merchantId: merchantId,
+		response:   response,
+		status:     status,
+		attempts:   attempt,
+		latency:    time.Since(start),
+		err:        err,
+	})
 	return response, err
 }
+
+// transactionInfo identifies an online transaction, whatever its type.
+type transactionInfo struct {
+	// Type is the element name of the transaction, e.g. "sale".
+	Type string
+	// Id is the id attribute, which the gateway uses for duplicate
+	// detection.
+	Id          string
+	ReportGroup string
+	OrderId     string
+}
+
+func describeTransaction(payload interface{}) transactionInfo {
+	switch p := payload.(type) {
+	case *Authorization:
+		return transactionInfo{Type: "authorization", Id: p.Id, ReportGroup: p.ReportGroup, OrderId: p.OrderId}
+	case *AuthReversal:
+		return transactionInfo{Type: "authReversal", Id: p.Id, ReportGroup: p.ReportGroup}
+	case *CancelSubscription:
+		return transactionInfo{Type: "cancelSubscription"}
+	case *Capture:
+		return transactionInfo{Type: "capture", Id: p.Id, ReportGroup: p.ReportGroup}
+	case *CaptureGivenAuth:
+		return transactionInfo{Type: "captureGivenAuth", Id: p.Id, ReportGroup: p.ReportGroup, OrderId: p.OrderId}
+	case *CreatePlan:
+		return transactionInfo{Type: "createPlan"}
+	case *Credit:
+		return transactionInfo{Type: "credit", Id: p.Id, ReportGroup: p.ReportGroup, OrderId: p.OrderId}
+	case *EcheckCredit:
+		return transactionInfo{Type: "echeckCredit", Id: p.Id, ReportGroup: p.ReportGroup}
+	case *EcheckSale:
+		return transactionInfo{Type: "echeckSale", Id: p.Id, ReportGroup: p.ReportGroup, OrderId: p.OrderId}
+	case *EcheckVoid:
+		return transactionInfo{Type: "echeckVoid", Id: p.Id, ReportGroup: p.ReportGroup}
+	case *ForceCapture:
+		return transactionInfo{Type: "forceCapture", Id: p.Id, ReportGroup: p.ReportGroup, OrderId: p.OrderId}
+	case *QueryTransaction:
+		return transactionInfo{Type: "queryTransaction", Id: p.Id, ReportGroup: p.ReportGroup}
+	case *RegisterTokenRequest:
+		return transactionInfo{Type: "registerTokenRequest", Id: p.Id, ReportGroup: p.ReportGroup, OrderId: p.OrderId}
+	case *Sale:
+		return transactionInfo{Type: "sale", Id: p.Id, ReportGroup: p.ReportGroup, OrderId: p.OrderId}
+	case *UpdatePlan:
+		return transactionInfo{Type: "updatePlan"}
+	case *UpdateSubscription:
+		return transactionInfo{Type: "updateSubscription"}
+	case *Void:
+		return transactionInfo{Type: "void", Id: p.Id, ReportGroup: p.ReportGroup}
+	}
+	return transactionInfo{}
+}
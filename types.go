@@ -3,51 +3,82 @@ package worldpay
 import (
 	"encoding/xml"
 	"io"
+	"log/slog"
 	"net/http"
 	"sync"
+	"time"
 )
 
 type (
 	Client struct {
-		Client     *http.Client
-		Login      string
-		Password   string
-		ApiBase    string
-		MerchantId string
-		Log        io.Writer
-		mu         sync.Mutex
+		Client              *http.Client
+		Login               string
+		Password            string
+		ApiBase             string
+		MerchantId          string
+		Router              MerchantRouter
+		UserAgent           string
+		Log                 io.Writer
+		LogUnmasked         bool
+		Retry               *RetryPolicy
+		AutoReversal        func(result AutoReversalResult)
+		Schema              SchemaVersion
+		ValidateRequests    bool
+		StructuredLogger    *slog.Logger
+		credentialsProvider CredentialsProvider
+		timeout             time.Duration
+		mu                  sync.Mutex
 	}
 
 	LitleOnlineRequest struct {
-		XMLName        xml.Name       `xml:"litleOnlineRequest"`
-		Version        string         `xml:"version,attr"`
-		XmlNamespace   string         `xml:"xmlns,attr"`
-		MerchantId     string         `xml:"merchantId,attr"`
-		Authentication Authentication `xml:"authentication"`
-		Authorization  *Authorization `xml:"authorization"`
-		Capture        *Capture       `xml:"capture"`
-		Credit         *Credit        `xml:"credit"`
-		EcheckCredit   *EcheckCredit  `xml:"echeckCredit"`
-		EcheckSale     *EcheckSale    `xml:"echeckSale"`
-		EcheckVoid     *EcheckVoid    `xml:"echeckVoid"`
-		Sale           *Sale          `xml:"sale"`
-		Void           *Void          `xml:"void"`
+		XMLName            xml.Name              `xml:"litleOnlineRequest"`
+		Version            string                `xml:"version,attr"`
+		XmlNamespace       string                `xml:"xmlns,attr"`
+		MerchantId         string                `xml:"merchantId,attr"`
+		Authentication     Authentication        `xml:"authentication"`
+		Authorization      *Authorization        `xml:"authorization"`
+		AuthReversal       *AuthReversal         `xml:"authReversal"`
+		CancelSubscription *CancelSubscription   `xml:"cancelSubscription"`
+		Capture            *Capture              `xml:"capture"`
+		CaptureGivenAuth   *CaptureGivenAuth     `xml:"captureGivenAuth"`
+		CreatePlan         *CreatePlan           `xml:"createPlan"`
+		Credit             *Credit               `xml:"credit"`
+		EcheckCredit       *EcheckCredit         `xml:"echeckCredit"`
+		EcheckSale         *EcheckSale           `xml:"echeckSale"`
+		EcheckVoid         *EcheckVoid           `xml:"echeckVoid"`
+		ForceCapture       *ForceCapture         `xml:"forceCapture"`
+		QueryTransaction   *QueryTransaction     `xml:"queryTransaction"`
+		RegisterToken      *RegisterTokenRequest `xml:"registerTokenRequest"`
+		Sale               *Sale                 `xml:"sale"`
+		UpdatePlan         *UpdatePlan           `xml:"updatePlan"`
+		UpdateSubscription *UpdateSubscription   `xml:"updateSubscription"`
+		Void               *Void                 `xml:"void"`
 	}
 
 	LitleOnlineResponse struct {
-		XMLName               xml.Name               `xml:"litleOnlineResponse"`
-		Version               string                 `xml:"version,attr"`
-		XmlNS                 string                 `xml:"xmlns,attr"`
-		Response              string                 `xml:"response,attr"`
-		Message               string                 `xml:"message,attr"`
-		AuthorizationResponse *AuthorizationResponse `xml:"authorizationResponse,omitempty"`
-		CaptureResponse       *CaptureResponse       `xml:"captureResponse,omitempty"`
-		CreditResponse        *CreditResponse        `xml:"creditResponse,omitempty"`
-		EcheckCreditResponse  *EcheckCreditResponse  `xml:"echeckCreditResponse,omitempty"`
-		EcheckSaleResponse    *EcheckSaleResponse    `xml:"echeckSalesResponse,omitempty"`
-		EcheckVoidResponse    *EcheckVoidResponse    `xml:"echeckVoidResponse,omitempty"`
-		SaleResponse          *SaleResponse          `xml:"saleResponse,omitempty"`
-		VoidResponse          *VoidResponse          `xml:"voidResponse,omitempty"`
+		XMLName                             xml.Name                             `xml:"litleOnlineResponse"`
+		Version                             string                               `xml:"version,attr"`
+		XmlNS                               string                               `xml:"xmlns,attr"`
+		Response                            string                               `xml:"response,attr"`
+		Message                             string                               `xml:"message,attr"`
+		AuthorizationResponse               *AuthorizationResponse               `xml:"authorizationResponse,omitempty"`
+		AuthReversalResponse                *AuthReversalResponse                `xml:"authReversalResponse,omitempty"`
+		CancelSubscriptionResponse          *CancelSubscriptionResponse          `xml:"cancelSubscriptionResponse,omitempty"`
+		CaptureResponse                     *CaptureResponse                     `xml:"captureResponse,omitempty"`
+		CaptureGivenAuthResponse            *CaptureGivenAuthResponse            `xml:"captureGivenAuthResponse,omitempty"`
+		CreatePlanResponse                  *CreatePlanResponse                  `xml:"createPlanResponse,omitempty"`
+		CreditResponse                      *CreditResponse                      `xml:"creditResponse,omitempty"`
+		EcheckCreditResponse                *EcheckCreditResponse                `xml:"echeckCreditResponse,omitempty"`
+		EcheckSaleResponse                  *EcheckSaleResponse                  `xml:"echeckSalesResponse,omitempty"`
+		EcheckVoidResponse                  *EcheckVoidResponse                  `xml:"echeckVoidResponse,omitempty"`
+		ForceCaptureResponse                *ForceCaptureResponse                `xml:"forceCaptureResponse,omitempty"`
+		QueryTransactionResponse            *QueryTransactionResponse            `xml:"queryTransactionResponse,omitempty"`
+		QueryTransactionUnavailableResponse *QueryTransactionUnavailableResponse `xml:"queryTransactionUnavailableResponse,omitempty"`
+		RegisterTokenResponse               *RegisterTokenResponse               `xml:"registerTokenResponse,omitempty"`
+		SaleResponse                        *SaleResponse                        `xml:"saleResponse,omitempty"`
+		UpdatePlanResponse                  *UpdatePlanResponse                  `xml:"updatePlanResponse,omitempty"`
+		UpdateSubscriptionResponse          *UpdateSubscriptionResponse          `xml:"updateSubscriptionResponse,omitempty"`
+		VoidResponse                        *VoidResponse                        `xml:"voidResponse,omitempty"`
 	}
 
 	Authentication struct {
@@ -65,7 +96,25 @@ type (
 		OrderSource              string                    `xml:"orderSource"`
 		BillToAddress            Address                   `xml:"billToAddress"`
 		Card                     Card                      `xml:"card"`
+		Token                    *Token                    `xml:"token"`
+		Paypage                  *Paypage                  `xml:"paypage"`
 		CardholderAuthentication *CardholderAuthentication `xml:"cardholderAuthentication"`
+		ProcessingType           string                    `xml:"processingType,omitempty"`
+	}
+
+	AuthReversal struct {
+		XMLName      xml.Name `xml:"authReversal"`
+		Id           string   `xml:"id,attr"`
+		ReportGroup  string   `xml:"reportGroup,attr"`
+		CustomerId   string   `xml:"customerId,attr"`
+		LitleTxnId   string   `xml:"litleTxnId"`
+		Amount       *int     `xml:"amount,omitempty"`
+		ActionReason string   `xml:"actionReason,omitempty"`
+	}
+
+	CancelSubscription struct {
+		XMLName        xml.Name `xml:"cancelSubscription"`
+		SubscriptionId string   `xml:"subscriptionId"`
 	}
 
 	Capture struct {
@@ -79,13 +128,46 @@ type (
 		EnhancedData *EnhancedData `xml:"enhancedData"`
 	}
 
+	CaptureGivenAuth struct {
+		XMLName         xml.Name        `xml:"captureGivenAuth"`
+		Id              string          `xml:"id,attr"`
+		ReportGroup     string          `xml:"reportGroup,attr"`
+		CustomerId      string          `xml:"customerId,attr"`
+		OrderId         string          `xml:"orderId"`
+		AuthInformation AuthInformation `xml:"authInformation"`
+		Amount          int             `xml:"amount"`
+		OrderSource     string          `xml:"orderSource"`
+		BillToAddress   Address         `xml:"billToAddress"`
+		Card            Card            `xml:"card"`
+		CustomBilling   *CustomBilling  `xml:"customBilling"`
+		EnhancedData    *EnhancedData   `xml:"enhancedData"`
+	}
+
+	CreatePlan struct {
+		XMLName                xml.Name `xml:"createPlan"`
+		PlanCode               string   `xml:"planCode"`
+		Name                   string   `xml:"name"`
+		Description            string   `xml:"description,omitempty"`
+		IntervalType           string   `xml:"intervalType"`
+		Amount                 int      `xml:"amount"`
+		NumberOfPayments       *int     `xml:"numberOfPayments,omitempty"`
+		TrialNumberOfIntervals *int     `xml:"trialNumberOfIntervals,omitempty"`
+		TrialIntervalType      string   `xml:"trialIntervalType,omitempty"`
+		Active                 *bool    `xml:"active,omitempty"`
+	}
+
 	Credit struct {
-		XMLName     xml.Name `xml:"credit"`
-		Id          string   `xml:"id,attr"`
-		ReportGroup string   `xml:"reportGroup,attr"`
-		CustomerId  string   `xml:"customerId,attr"`
-		LitleTxnId  string   `xml:"litleTxnId"`
-		Amount      *int     `xml:"amount,omitempty"`
+		XMLName       xml.Name `xml:"credit"`
+		Id            string   `xml:"id,attr"`
+		ReportGroup   string   `xml:"reportGroup,attr"`
+		CustomerId    string   `xml:"customerId,attr"`
+		LitleTxnId    string   `xml:"litleTxnId,omitempty"`
+		OrderId       string   `xml:"orderId,omitempty"`
+		Amount        *int     `xml:"amount,omitempty"`
+		OrderSource   string   `xml:"orderSource,omitempty"`
+		BillToAddress *Address `xml:"billToAddress"`
+		Card          *Card    `xml:"card"`
+		Token         *Token   `xml:"token"`
 	}
 
 	EcheckSale struct {
@@ -117,6 +199,42 @@ type (
 		LitleTxnId  string   `xml:"litleTxnId"`
 	}
 
+	ForceCapture struct {
+		XMLName       xml.Name       `xml:"forceCapture"`
+		Id            string         `xml:"id,attr"`
+		ReportGroup   string         `xml:"reportGroup,attr"`
+		CustomerId    string         `xml:"customerId,attr"`
+		OrderId       string         `xml:"orderId"`
+		Amount        int            `xml:"amount"`
+		OrderSource   string         `xml:"orderSource"`
+		BillToAddress Address        `xml:"billToAddress"`
+		Card          Card           `xml:"card"`
+		CustomBilling *CustomBilling `xml:"customBilling"`
+		EnhancedData  *EnhancedData  `xml:"enhancedData"`
+	}
+
+	QueryTransaction struct {
+		XMLName        xml.Name `xml:"queryTransaction"`
+		Id             string   `xml:"id,attr"`
+		ReportGroup    string   `xml:"reportGroup,attr"`
+		CustomerId     string   `xml:"customerId,attr"`
+		OrigId         string   `xml:"origId"`
+		OrigActionType string   `xml:"origActionType"`
+		OrigLitleTxnId string   `xml:"origLitleTxnId,omitempty"`
+	}
+
+	RegisterTokenRequest struct {
+		XMLName               xml.Name `xml:"registerTokenRequest"`
+		Id                    string   `xml:"id,attr"`
+		ReportGroup           string   `xml:"reportGroup,attr"`
+		CustomerId            string   `xml:"customerId,attr"`
+		OrderId               string   `xml:"orderId"`
+		AccountNumber         string   `xml:"accountNumber,omitempty"`
+		EcheckForToken        *Echeck  `xml:"echeckForToken"`
+		PaypageRegistrationId string   `xml:"paypageRegistrationId,omitempty"`
+		CardValidationNum     string   `xml:"cardValidationNum,omitempty"`
+	}
+
 	Sale struct {
 		XMLName                  xml.Name                  `xml:"sale"`
 		Id                       string                    `xml:"id,attr"`
@@ -127,9 +245,30 @@ type (
 		OrderSource              string                    `xml:"orderSource"`
 		BillToAddress            Address                   `xml:"billToAddress"`
 		Card                     Card                      `xml:"card"`
+		Token                    *Token                    `xml:"token"`
+		Paypage                  *Paypage                  `xml:"paypage"`
 		CardholderAuthentication *CardholderAuthentication `xml:"cardholderAuthentication"`
 		CustomBilling            *CustomBilling            `xml:"customBilling"`
 		EnhancedData             *EnhancedData             `xml:"enhancedData"`
+		RecurringRequest         *RecurringRequest         `xml:"recurringRequest"`
+		ProcessingType           string                    `xml:"processingType,omitempty"`
+	}
+
+	UpdatePlan struct {
+		XMLName  xml.Name `xml:"updatePlan"`
+		PlanCode string   `xml:"planCode"`
+		Active   bool     `xml:"active"`
+	}
+
+	UpdateSubscription struct {
+		XMLName        xml.Name `xml:"updateSubscription"`
+		SubscriptionId string   `xml:"subscriptionId"`
+		PlanCode       string   `xml:"planCode,omitempty"`
+		BillToAddress  *Address `xml:"billToAddress"`
+		Card           *Card    `xml:"card"`
+		Token          *Token   `xml:"token"`
+		Paypage        *Paypage `xml:"paypage"`
+		BillingDate    string   `xml:"billingDate,omitempty"`
 	}
 
 	Void struct {
@@ -166,6 +305,55 @@ type (
 		CardValidationNum string `xml:"cardValidationNum"`
 	}
 
+	AuthInformation struct {
+		AuthDate    string       `xml:"authDate"`
+		AuthCode    string       `xml:"authCode"`
+		FraudResult *FraudResult `xml:"fraudResult"`
+		AuthAmount  *int         `xml:"authAmount,omitempty"`
+	}
+
+	Token struct {
+		LitleToken        string `xml:"litleToken"`
+		ExpDate           string `xml:"expDate,omitempty"`
+		CardValidationNum string `xml:"cardValidationNum,omitempty"`
+	}
+
+	Paypage struct {
+		PaypageRegistrationId string `xml:"paypageRegistrationId"`
+		ExpDate               string `xml:"expDate,omitempty"`
+		CardValidationNum     string `xml:"cardValidationNum,omitempty"`
+		Type                  string `xml:"type,omitempty"`
+	}
+
+	RecurringRequest struct {
+		Subscription Subscription `xml:"subscription"`
+	}
+
+	Subscription struct {
+		PlanCode         string     `xml:"planCode"`
+		NumberOfPayments *int       `xml:"numberOfPayments,omitempty"`
+		StartDate        string     `xml:"startDate,omitempty"`
+		Amount           *int       `xml:"amount,omitempty"`
+		CreateDiscounts  []Discount `xml:"createDiscount"`
+		CreateAddOns     []AddOn    `xml:"createAddOn"`
+	}
+
+	Discount struct {
+		DiscountCode string `xml:"discountCode"`
+		Name         string `xml:"name"`
+		Amount       int    `xml:"amount"`
+		StartDate    string `xml:"startDate"`
+		EndDate      string `xml:"endDate"`
+	}
+
+	AddOn struct {
+		AddOnCode string `xml:"addOnCode"`
+		Name      string `xml:"name"`
+		Amount    int    `xml:"amount"`
+		StartDate string `xml:"startDate"`
+		EndDate   string `xml:"endDate"`
+	}
+
 	CardholderAuthentication struct {
 		AuthenticationValue         string `xml:"authenticationValue"`
 		AuthenticationTransactionId string `xml:"authenticationTransactionId"`
@@ -230,9 +418,32 @@ type (
 		ApprovedAmount       string          `xml:"approvedAmount"`
 		NetworkTransactionId string          `xml:"networkTransactionId"`
 		FraudResult          *FraudResult    `xml:"fraudResult"`
+		TokenResponse        *TokenResponse  `xml:"tokenResponse"`
 		AccountUpdater       *AccountUpdater `xml:"accountUpdater"`
 	}
 
+	AuthReversalResponse struct {
+		XMLName      xml.Name `xml:"authReversalResponse"`
+		Id           string   `xml:"id,attr"`
+		ReportGroup  string   `xml:"reportGroup,attr"`
+		CustomerId   string   `xml:"customerId,attr"`
+		LitleTxnId   string   `xml:"litleTxnId"`
+		OrderId      string   `xml:"orderId"`
+		Response     string   `xml:"response"`
+		ResponseTime string   `xml:"responseTime"`
+		PostDate     string   `xml:"postDate"`
+		Message      string   `xml:"message"`
+	}
+
+	CancelSubscriptionResponse struct {
+		XMLName        xml.Name `xml:"cancelSubscriptionResponse"`
+		LitleTxnId     string   `xml:"litleTxnId"`
+		Response       string   `xml:"response"`
+		Message        string   `xml:"message"`
+		ResponseTime   string   `xml:"responseTime"`
+		SubscriptionId string   `xml:"subscriptionId"`
+	}
+
 	CaptureResponse struct {
 		XMLName        xml.Name        `xml:"captureResponse"`
 		Id             string          `xml:"id,attr"`
@@ -246,6 +457,29 @@ type (
 		AccountUpdater *AccountUpdater `xml:"accountUpdater"`
 	}
 
+	CaptureGivenAuthResponse struct {
+		XMLName        xml.Name        `xml:"captureGivenAuthResponse"`
+		Id             string          `xml:"id,attr"`
+		ReportGroup    string          `xml:"reportGroup,attr"`
+		CustomerId     string          `xml:"customerId,attr"`
+		LitleTxnId     string          `xml:"litleTxnId"`
+		OrderId        string          `xml:"orderId"`
+		Response       string          `xml:"response"`
+		ResponseTime   string          `xml:"responseTime"`
+		PostDate       string          `xml:"postDate"`
+		Message        string          `xml:"message"`
+		AccountUpdater *AccountUpdater `xml:"accountUpdater"`
+	}
+
+	CreatePlanResponse struct {
+		XMLName      xml.Name `xml:"createPlanResponse"`
+		LitleTxnId   string   `xml:"litleTxnId"`
+		Response     string   `xml:"response"`
+		Message      string   `xml:"message"`
+		ResponseTime string   `xml:"responseTime"`
+		PlanCode     string   `xml:"planCode"`
+	}
+
 	CreditResponse struct {
 		XMLName      xml.Name `xml:"creditResponse"`
 		Id           string   `xml:"id,attr"`
@@ -292,21 +526,110 @@ type (
 		PostDate     string   `xml:"postDate"`
 	}
 
+	ForceCaptureResponse struct {
+		XMLName        xml.Name        `xml:"forceCaptureResponse"`
+		Id             string          `xml:"id,attr"`
+		ReportGroup    string          `xml:"reportGroup,attr"`
+		CustomerId     string          `xml:"customerId,attr"`
+		LitleTxnId     string          `xml:"litleTxnId"`
+		OrderId        string          `xml:"orderId"`
+		Response       string          `xml:"response"`
+		ResponseTime   string          `xml:"responseTime"`
+		PostDate       string          `xml:"postDate"`
+		Message        string          `xml:"message"`
+		AccountUpdater *AccountUpdater `xml:"accountUpdater"`
+	}
+
+	QueryTransactionResponse struct {
+		XMLName      xml.Name                `xml:"queryTransactionResponse"`
+		Id           string                  `xml:"id,attr"`
+		ReportGroup  string                  `xml:"reportGroup,attr"`
+		CustomerId   string                  `xml:"customerId,attr"`
+		Response     string                  `xml:"response"`
+		ResponseTime string                  `xml:"responseTime"`
+		Message      string                  `xml:"message"`
+		MatchCount   int                     `xml:"matchCount"`
+		Results      QueryTransactionResults `xml:"results_max10"`
+	}
+
+	QueryTransactionResults struct {
+		AuthorizationResponses    []*AuthorizationResponse    `xml:"authorizationResponse"`
+		AuthReversalResponses     []*AuthReversalResponse     `xml:"authReversalResponse"`
+		CaptureResponses          []*CaptureResponse          `xml:"captureResponse"`
+		CaptureGivenAuthResponses []*CaptureGivenAuthResponse `xml:"captureGivenAuthResponse"`
+		CreditResponses           []*CreditResponse           `xml:"creditResponse"`
+		EcheckCreditResponses     []*EcheckCreditResponse     `xml:"echeckCreditResponse"`
+		EcheckSaleResponses       []*EcheckSaleResponse       `xml:"echeckSalesResponse"`
+		EcheckVoidResponses       []*EcheckVoidResponse       `xml:"echeckVoidResponse"`
+		ForceCaptureResponses     []*ForceCaptureResponse     `xml:"forceCaptureResponse"`
+		RegisterTokenResponses    []*RegisterTokenResponse    `xml:"registerTokenResponse"`
+		SaleResponses             []*SaleResponse             `xml:"saleResponse"`
+		VoidResponses             []*VoidResponse             `xml:"voidResponse"`
+	}
+
+	QueryTransactionUnavailableResponse struct {
+		XMLName      xml.Name `xml:"queryTransactionUnavailableResponse"`
+		Id           string   `xml:"id,attr"`
+		ReportGroup  string   `xml:"reportGroup,attr"`
+		CustomerId   string   `xml:"customerId,attr"`
+		LitleTxnId   string   `xml:"litleTxnId"`
+		Response     string   `xml:"response"`
+		ResponseTime string   `xml:"responseTime"`
+		Message      string   `xml:"message"`
+	}
+
+	RegisterTokenResponse struct {
+		XMLName             xml.Name `xml:"registerTokenResponse"`
+		Id                  string   `xml:"id,attr"`
+		ReportGroup         string   `xml:"reportGroup,attr"`
+		CustomerId          string   `xml:"customerId,attr"`
+		LitleTxnId          string   `xml:"litleTxnId"`
+		OrderId             string   `xml:"orderId"`
+		LitleToken          string   `xml:"litleToken"`
+		Bin                 string   `xml:"bin"`
+		Type                string   `xml:"type"`
+		Response            string   `xml:"response"`
+		Message             string   `xml:"message"`
+		ResponseTime        string   `xml:"responseTime"`
+		EcheckAccountSuffix string   `xml:"eCheckAccountSuffix"`
+	}
+
 	SaleResponse struct {
-		XMLName              xml.Name        `xml:"saleResponse"`
-		Id                   string          `xml:"id,attr"`
-		ReportGroup          string          `xml:"reportGroup,attr"`
-		CustomerId           string          `xml:"customerId,attr"`
-		LitleTxnId           string          `xml:"litleTxnId"`
-		Response             string          `xml:"response"`
-		OrderId              string          `xml:"orderId"`
-		ResponseTime         string          `xml:"responseTime"`
-		PostDate             string          `xml:"postDate"`
-		Message              string          `xml:"message"`
-		AuthCode             string          `xml:"authCode"`
-		NetworkTransactionId string          `xml:"networkTransactionId"`
-		FraudResult          *FraudResult    `xml:"fraudResult"`
-		AccountUpdater       *AccountUpdater `xml:"accountUpdater"`
+		XMLName              xml.Name           `xml:"saleResponse"`
+		Id                   string             `xml:"id,attr"`
+		ReportGroup          string             `xml:"reportGroup,attr"`
+		CustomerId           string             `xml:"customerId,attr"`
+		LitleTxnId           string             `xml:"litleTxnId"`
+		Response             string             `xml:"response"`
+		OrderId              string             `xml:"orderId"`
+		ResponseTime         string             `xml:"responseTime"`
+		PostDate             string             `xml:"postDate"`
+		Message              string             `xml:"message"`
+		AuthCode             string             `xml:"authCode"`
+		NetworkTransactionId string             `xml:"networkTransactionId"`
+		FraudResult          *FraudResult       `xml:"fraudResult"`
+		TokenResponse        *TokenResponse     `xml:"tokenResponse"`
+		AccountUpdater       *AccountUpdater    `xml:"accountUpdater"`
+		RecurringResponse    *RecurringResponse `xml:"recurringResponse"`
+	}
+
+	UpdatePlanResponse struct {
+		XMLName      xml.Name `xml:"updatePlanResponse"`
+		LitleTxnId   string   `xml:"litleTxnId"`
+		Response     string   `xml:"response"`
+		Message      string   `xml:"message"`
+		ResponseTime string   `xml:"responseTime"`
+		PlanCode     string   `xml:"planCode"`
+	}
+
+	UpdateSubscriptionResponse struct {
+		XMLName        xml.Name       `xml:"updateSubscriptionResponse"`
+		LitleTxnId     string         `xml:"litleTxnId"`
+		Response       string         `xml:"response"`
+		Message        string         `xml:"message"`
+		ResponseTime   string         `xml:"responseTime"`
+		SubscriptionId string         `xml:"subscriptionId"`
+		TokenResponse  *TokenResponse `xml:"tokenResponse"`
 	}
 
 	VoidResponse struct {
@@ -326,12 +649,125 @@ type (
 		AuthenticationResult string `xml:"authenticationResult"`
 	}
 
+	TokenResponse struct {
+		LitleToken          string `xml:"litleToken"`
+		TokenResponseCode   string `xml:"tokenResponseCode"`
+		TokenMessage        string `xml:"tokenMessage"`
+		Type                string `xml:"type"`
+		Bin                 string `xml:"bin"`
+		EcheckAccountSuffix string `xml:"eCheckAccountSuffix"`
+	}
+
+	RecurringResponse struct {
+		SubscriptionId  string `xml:"subscriptionId"`
+		ResponseCode    string `xml:"responseCode"`
+		ResponseMessage string `xml:"responseMessage"`
+		RecurringTxnId  string `xml:"recurringTxnId"`
+	}
+
 	AccountUpdater struct {
 		OriginalCardInfo Card `xml:"originalCardInfo"`
 		NewCardInfo      Card `xml:"newCardInfo"`
 	}
 )
 
+// MarshalXML omits an empty card so that another payment source, such as a
+// token, can be sent in its place.
+func (c Card) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	if c == (Card{}) {
+		return nil
+	}
+
+	type card Card
+	return e.EncodeElement(card(c), start)
+}
+
 func (r *LitleOnlineResponse) HasError() bool {
 	return r.Response != "0"
 }
+
+// TransactionResponse returns the transaction level response contained in
+// the document, or nil if there is none.
+func (r *LitleOnlineResponse) TransactionResponse() TransactionResponse {
+	switch {
+	case r.AuthorizationResponse != nil:
+		return r.AuthorizationResponse
+	case r.AuthReversalResponse != nil:
+		return r.AuthReversalResponse
+	case r.CancelSubscriptionResponse != nil:
+		return r.CancelSubscriptionResponse
+	case r.CaptureResponse != nil:
+		return r.CaptureResponse
+	case r.CaptureGivenAuthResponse != nil:
+		return r.CaptureGivenAuthResponse
+	case r.CreatePlanResponse != nil:
+		return r.CreatePlanResponse
+	case r.CreditResponse != nil:
+		return r.CreditResponse
+	case r.EcheckCreditResponse != nil:
+		return r.EcheckCreditResponse
+	case r.EcheckSaleResponse != nil:
+		return r.EcheckSaleResponse
+	case r.EcheckVoidResponse != nil:
+		return r.EcheckVoidResponse
+	case r.ForceCaptureResponse != nil:
+		return r.ForceCaptureResponse
+	case r.QueryTransactionResponse != nil:
+		return r.QueryTransactionResponse
+	case r.QueryTransactionUnavailableResponse != nil:
+		return r.QueryTransactionUnavailableResponse
+	case r.RegisterTokenResponse != nil:
+		return r.RegisterTokenResponse
+	case r.SaleResponse != nil:
+		return r.SaleResponse
+	case r.UpdatePlanResponse != nil:
+		return r.UpdatePlanResponse
+	case r.UpdateSubscriptionResponse != nil:
+		return r.UpdateSubscriptionResponse
+	case r.VoidResponse != nil:
+		return r.VoidResponse
+	}
+	return nil
+}
+
+// Responses returns every matched transaction response in a single list.
+func (r *QueryTransactionResults) Responses() []TransactionResponse {
+	var responses []TransactionResponse
+	for _, v := range r.AuthorizationResponses {
+		responses = append(responses, v)
+	}
+	for _, v := range r.AuthReversalResponses {
+		responses = append(responses, v)
+	}
+	for _, v := range r.CaptureResponses {
+		responses = append(responses, v)
+	}
+	for _, v := range r.CaptureGivenAuthResponses {
+		responses = append(responses, v)
+	}
+	for _, v := range r.CreditResponses {
+		responses = append(responses, v)
+	}
+	for _, v := range r.EcheckCreditResponses {
+		responses = append(responses, v)
+	}
+	for _, v := range r.EcheckSaleResponses {
+		responses = append(responses, v)
+	}
+	for _, v := range r.EcheckVoidResponses {
+		responses = append(responses, v)
+	}
+	for _, v := range r.ForceCaptureResponses {
+		responses = append(responses, v)
+	}
+	for _, v := range r.RegisterTokenResponses {
+		responses = append(responses, v)
+	}
+	for _, v := range r.SaleResponses {
+		responses = append(responses, v)
+	}
+	for _, v := range r.VoidResponses {
+		responses = append(responses, v)
+	}
+	return responses
+}
@@ -0,0 +1,461 @@
+package worldpay
+
+import (
+	"fmt"
+	"strings"
+)
+
+// maxAmount is the largest amount the schema allows, which is limited to 12
+// digits.
+const maxAmount = 999999999999
+
+// Enumerations from the cnpAPI schema.
+var (
+	cardTypes       = []string{"MC", "VI", "AX", "DC", "DI", "PP", "JC", "BL", "EC", "GC"}
+	accTypes        = []string{"Checking", "Savings", "Corporate", "Corp Savings"}
+	intervalTypes   = []string{"ANNUAL", "SEMIANNUAL", "QUARTERLY", "MONTHLY", "WEEKLY"}
+	trialTypes      = []string{"MONTH", "DAY"}
+	actionReasons   = []string{"SUSPECT_FRAUD"}
+	orderSources    = []string{"ecommerce", "installment", "mailorder", "recurring", "retail", "telephone", "3dsAuthenticated", "3dsAttempted", "recurringtel", "echeckppd", "applepay", "androidpay", "samsungpay", "visacheckout", "masterpass", "item"}
+	processingTypes = []string{"accountFunding", "initialRecurring", "initialInstallment", "initialCOF", "merchantInitiatedCOF", "cardholderInitiatedCOF"}
+)
+
+type validatable interface {
+	Validate() error
+}
+
+// validator collects every violation found in a request, so they can be
+// reported together.
+type validator struct {
+	errs []FieldError
+}
+
+func (v *validator) add(field, format string, args ...interface{}) {
+	v.errs = append(v.errs, FieldError{Field: field, Message: fmt.Sprintf(format, args...)})
+}
+
+func (v *validator) required(field, value string) {
+	if value == "" {
+		v.add(field, "is required")
+	}
+}
+
+func (v *validator) maxLength(field, value string, max int) {
+	if len(value) > max {
+		v.add(field, "must be at most %d characters", max)
+	}
+}
+
+func (v *validator) length(field, value string, min, max int) {
+	if value != "" && (len(value) < min || len(value) > max) {
+		v.add(field, "must be between %d and %d characters", min, max)
+	}
+}
+
+func (v *validator) digits(field, value string, max int) {
+	if value == "" {
+		return
+	}
+	if strings.Trim(value, "0123456789") != "" || len(value) > max {
+		v.add(field, "must be at most %d digits", max)
+	}
+}
+
+// expDate checks an MMYY expiration date.
+func (v *validator) expDate(field, value string) {
+	if value != "" && (len(value) != 4 || strings.Trim(value, "0123456789") != "" || value[:2] < "01" || value[:2] > "12") {
+		v.add(field, "must be a MMYY date")
+	}
+}
+
+func (v *validator) oneOf(field, value string, values []string) {
+	if value == "" {
+		return
+	}
+	for _, allowed := range values {
+		if value == allowed {
+			return
+		}
+	}
+	v.add(field, "must be one of %s", strings.Join(values, ", "))
+}
+
+func (v *validator) amount(field string, value int) {
+	if value < 0 || value > maxAmount {
+		v.add(field, "must be between 0 and %d", maxAmount)
+	}
+}
+
+func (v *validator) optionalAmount(field string, value *int) {
+	if value != nil {
+		v.amount(field, *value)
+	}
+}
+
+func (v *validator) attributes(id, reportGroup, customerId string) {
+	v.maxLength("id", id, 25)
+	v.maxLength("reportGroup", reportGroup, 25)
+	v.maxLength("customerId", customerId, 50)
+}
+
+func (v *validator) order(orderId string, amount int, orderSource string) {
+	v.required("orderId", orderId)
+	v.maxLength("orderId", orderId, 25)
+	v.amount("amount", amount)
+	v.required("orderSource", orderSource)
+	v.oneOf("orderSource", orderSource, orderSources)
+}
+
+func (v *validator) address(field string, a Address) {
+	v.maxLength(field+".name", a.Name, 100)
+	v.maxLength(field+".addressLine1", a.AddressLine1, 35)
+	v.maxLength(field+".addressLine2", a.AddressLine2, 35)
+	v.maxLength(field+".addressLine3", a.AddressLine3, 35)
+	v.maxLength(field+".city", a.City, 35)
+	v.maxLength(field+".state", a.State, 30)
+	v.maxLength(field+".zip", a.Zip, 20)
+	v.maxLength(field+".country", a.Country, 3)
+	v.maxLength(field+".email", a.Email, 100)
+	v.maxLength(field+".phone", a.Phone, 20)
+}
+
+func (v *validator) card(field string, c Card) {
+	v.required(field+".type", c.Type)
+	v.oneOf(field+".type", c.Type, cardTypes)
+	v.required(field+".number", c.Number)
+	v.length(field+".number", c.Number, 13, 25)
+	v.expDate(field+".expDate", c.ExpDate)
+	v.maxLength(field+".cardValidationNum", c.CardValidationNum, 4)
+}
+
+func (v *validator) token(field string, t Token) {
+	v.required(field+".litleToken", t.LitleToken)
+	v.length(field+".litleToken", t.LitleToken, 13, 25)
+	v.expDate(field+".expDate", t.ExpDate)
+	v.maxLength(field+".cardValidationNum", t.CardValidationNum, 4)
+}
+
+func (v *validator) paypage(field string, p Paypage) {
+	v.required(field+".paypageRegistrationId", p.PaypageRegistrationId)
+	v.maxLength(field+".paypageRegistrationId", p.PaypageRegistrationId, 512)
+	v.expDate(field+".expDate", p.ExpDate)
+	v.maxLength(field+".cardValidationNum", p.CardValidationNum, 4)
+	v.oneOf(field+".type", p.Type, cardTypes)
+}
+
+// payment validates the card, token or paypage a transaction is paid with,
+// exactly one of which must be given.
+func (v *validator) payment(card Card, token *Token, paypage *Paypage) {
+	n := 0
+	if card != (Card{}) {
+		v.card("card", card)
+		n++
+	}
+	if token != nil {
+		v.token("token", *token)
+		n++
+	}
+	if paypage != nil {
+		v.paypage("paypage", *paypage)
+		n++
+	}
+	if n != 1 {
+		v.add("card", "exactly one of card, token or paypage is required")
+	}
+}
+
+// paymentSourceConflict rejects a Sale or Authorization with more than one of
+// card, token or paypage set. Unlike Validate it is always checked, since the
+// request would not say which of them is to be charged.
+func paymentSourceConflict(payload interface{}) error {
+	var (
+		transaction    string
+		card           Card
+		token, paypage bool
+	)
+	switch p := payload.(type) {
+	case *Authorization:
+		transaction, card, token, paypage = "authorization", p.Card, p.Token != nil, p.Paypage != nil
+	case *Sale:
+		transaction, card, token, paypage = "sale", p.Card, p.Token != nil, p.Paypage != nil
+	default:
+		return nil
+	}
+
+	n := 0
+	for _, set := range []bool{card != (Card{}), token, paypage} {
+		if set {
+			n++
+		}
+	}
+	if n > 1 {
+		var v validator
+		v.add("card", "only one of card, token or paypage may be set")
+		return v.err(transaction)
+	}
+	return nil
+}
+
+func (v *validator) echeck(field string, e Echeck) {
+	v.required(field+".accType", e.AccType)
+	v.oneOf(field+".accType", e.AccType, accTypes)
+	v.required(field+".accNum", e.AccNum)
+	v.maxLength(field+".accNum", e.AccNum, 17)
+	v.required(field+".routingNum", e.RoutingNum)
+	if e.RoutingNum != "" && (len(e.RoutingNum) != 9 || strings.Trim(e.RoutingNum, "0123456789") != "") {
+		v.add(field+".routingNum", "must be 9 digits")
+	}
+	if e.CheckNum != nil {
+		v.maxLength(field+".checkNum", *e.CheckNum, 15)
+	}
+}
+
+func (v *validator) litleTxnId(value string) {
+	v.required("litleTxnId", value)
+	v.digits("litleTxnId", value, 19)
+}
+
+func (v *validator) subscription(field string, s Subscription) {
+	v.required(field+".planCode", s.PlanCode)
+	v.length(field+".planCode", s.PlanCode, 1, 25)
+	if s.NumberOfPayments != nil && (*s.NumberOfPayments < 1 || *s.NumberOfPayments > 99) {
+		v.add(field+".numberOfPayments", "must be between 1 and 99")
+	}
+	v.optionalAmount(field+".amount", s.Amount)
+	for _, d := range s.CreateDiscounts {
+		v.required(field+".createDiscount.discountCode", d.DiscountCode)
+		v.maxLength(field+".createDiscount.discountCode", d.DiscountCode, 25)
+		v.maxLength(field+".createDiscount.name", d.Name, 100)
+		v.amount(field+".createDiscount.amount", d.Amount)
+	}
+	for _, a := range s.CreateAddOns {
+		v.required(field+".createAddOn.addOnCode", a.AddOnCode)
+		v.maxLength(field+".createAddOn.addOnCode", a.AddOnCode, 25)
+		v.maxLength(field+".createAddOn.name", a.Name, 100)
+		v.amount(field+".createAddOn.amount", a.Amount)
+	}
+}
+
+func (v *validator) err(transaction string) error {
+	if len(v.errs) == 0 {
+		return nil
+	}
+	return &ValidationError{Transaction: transaction, Errors: v.errs}
+}
+
+func (a *Authorization) Validate() error {
+	var v validator
+	v.attributes(a.Id, a.ReportGroup, a.CustomerId)
+	v.order(a.OrderId, a.Amount, a.OrderSource)
+	v.address("billToAddress", a.BillToAddress)
+	v.payment(a.Card, a.Token, a.Paypage)
+	v.oneOf("processingType", a.ProcessingType, processingTypes)
+	return v.err("authorization")
+}
+
+func (a *AuthReversal) Validate() error {
+	var v validator
+	v.attributes(a.Id, a.ReportGroup, a.CustomerId)
+	v.litleTxnId(a.LitleTxnId)
+	v.optionalAmount("amount", a.Amount)
+	v.oneOf("actionReason", a.ActionReason, actionReasons)
+	return v.err("authReversal")
+}
+
+func (s *CancelSubscription) Validate() error {
+	var v validator
+	v.required("subscriptionId", s.SubscriptionId)
+	v.digits("subscriptionId", s.SubscriptionId, 19)
+	return v.err("cancelSubscription")
+}
+
+func (c *Capture) Validate() error {
+	var v validator
+	v.attributes(c.Id, c.ReportGroup, c.CustomerId)
+	v.litleTxnId(c.LitleTxnId)
+	v.amount("amount", c.Amount)
+	return v.err("capture")
+}
+
+func (c *CaptureGivenAuth) Validate() error {
+	var v validator
+	v.attributes(c.Id, c.ReportGroup, c.CustomerId)
+	v.order(c.OrderId, c.Amount, c.OrderSource)
+	v.required("authInformation.authDate", c.AuthInformation.AuthDate)
+	v.required("authInformation.authCode", c.AuthInformation.AuthCode)
+	v.maxLength("authInformation.authCode", c.AuthInformation.AuthCode, 6)
+	v.optionalAmount("authInformation.authAmount", c.AuthInformation.AuthAmount)
+	v.address("billToAddress", c.BillToAddress)
+	v.card("card", c.Card)
+	return v.err("captureGivenAuth")
+}
+
+func (p *CreatePlan) Validate() error {
+	var v validator
+	v.required("planCode", p.PlanCode)
+	v.length("planCode", p.PlanCode, 1, 25)
+	v.required("name", p.Name)
+	v.maxLength("name", p.Name, 100)
+	v.maxLength("description", p.Description, 100)
+	v.required("intervalType", p.IntervalType)
+	v.oneOf("intervalType", p.IntervalType, intervalTypes)
+	v.amount("amount", p.Amount)
+	if p.NumberOfPayments != nil && (*p.NumberOfPayments < 1 || *p.NumberOfPayments > 99) {
+		v.add("numberOfPayments", "must be between 1 and 99")
+	}
+	if p.TrialNumberOfIntervals != nil && (*p.TrialNumberOfIntervals < 1 || *p.TrialNumberOfIntervals > 99) {
+		v.add("trialNumberOfIntervals", "must be between 1 and 99")
+	}
+	v.oneOf("trialIntervalType", p.TrialIntervalType, trialTypes)
+	return v.err("createPlan")
+}
+
+func (c *Credit) Validate() error {
+	var v validator
+	v.attributes(c.Id, c.ReportGroup, c.CustomerId)
+	v.optionalAmount("amount", c.Amount)
+	if c.LitleTxnId != "" {
+		v.digits("litleTxnId", c.LitleTxnId, 19)
+	} else {
+		// A credit without a litleTxnId is unlinked, and carries its own
+		// order and payment details.
+		v.required("orderId", c.OrderId)
+		v.maxLength("orderId", c.OrderId, 25)
+		if c.Amount == nil {
+			v.add("amount", "is required")
+		}
+		v.required("orderSource", c.OrderSource)
+		if c.Card == nil && c.Token == nil {
+			v.add("card", "exactly one of card or token is required")
+		}
+	}
+	v.oneOf("orderSource", c.OrderSource, orderSources)
+	if c.BillToAddress != nil {
+		v.address("billToAddress", *c.BillToAddress)
+	}
+	if c.Card != nil {
+		v.card("card", *c.Card)
+	}
+	if c.Token != nil {
+		v.token("token", *c.Token)
+	}
+	return v.err("credit")
+}
+
+func (e *EcheckCredit) Validate() error {
+	var v validator
+	v.attributes(e.Id, e.ReportGroup, e.CustomerId)
+	v.litleTxnId(e.LitleTxnId)
+	v.amount("amount", e.Amount)
+	return v.err("echeckCredit")
+}
+
+func (e *EcheckSale) Validate() error {
+	var v validator
+	v.attributes(e.Id, e.ReportGroup, e.CustomerId)
+	v.order(e.OrderId, e.Amount, e.OrderSource)
+	v.address("billToAddress", e.BillToAddress)
+	v.echeck("echeck", e.Echeck)
+	return v.err("echeckSale")
+}
+
+func (e *EcheckVoid) Validate() error {
+	var v validator
+	v.attributes(e.Id, e.ReportGroup, "")
+	v.litleTxnId(e.LitleTxnId)
+	return v.err("echeckVoid")
+}
+
+func (f *ForceCapture) Validate() error {
+	var v validator
+	v.attributes(f.Id, f.ReportGroup, f.CustomerId)
+	v.order(f.OrderId, f.Amount, f.OrderSource)
+	v.address("billToAddress", f.BillToAddress)
+	v.card("card", f.Card)
+	return v.err("forceCapture")
+}
+
+func (q *QueryTransaction) Validate() error {
+	var v validator
+	v.attributes(q.Id, q.ReportGroup, q.CustomerId)
+	v.required("origId", q.OrigId)
+	v.maxLength("origId", q.OrigId, 25)
+	v.required("origActionType", q.OrigActionType)
+	v.maxLength("origActionType", q.OrigActionType, 3)
+	v.digits("origLitleTxnId", q.OrigLitleTxnId, 19)
+	return v.err("queryTransaction")
+}
+
+func (r *RegisterTokenRequest) Validate() error {
+	var v validator
+	v.attributes(r.Id, r.ReportGroup, r.CustomerId)
+	v.maxLength("orderId", r.OrderId, 25)
+
+	n := 0
+	if r.AccountNumber != "" {
+		v.length("accountNumber", r.AccountNumber, 13, 25)
+		n++
+	}
+	if r.EcheckForToken != nil {
+		v.required("echeckForToken.accNum", r.EcheckForToken.AccNum)
+		v.maxLength("echeckForToken.accNum", r.EcheckForToken.AccNum, 17)
+		v.required("echeckForToken.routingNum", r.EcheckForToken.RoutingNum)
+		n++
+	}
+	if r.PaypageRegistrationId != "" {
+		v.maxLength("paypageRegistrationId", r.PaypageRegistrationId, 512)
+		n++
+	}
+	if n != 1 {
+		v.add("accountNumber", "exactly one of accountNumber, echeckForToken or paypageRegistrationId is required")
+	}
+	v.maxLength("cardValidationNum", r.CardValidationNum, 4)
+	return v.err("registerTokenRequest")
+}
+
+func (s *Sale) Validate() error {
+	var v validator
+	v.attributes(s.Id, s.ReportGroup, s.CustomerId)
+	v.order(s.OrderId, s.Amount, s.OrderSource)
+	v.address("billToAddress", s.BillToAddress)
+	v.payment(s.Card, s.Token, s.Paypage)
+	if s.RecurringRequest != nil {
+		v.subscription("recurringRequest.subscription", s.RecurringRequest.Subscription)
+	}
+	v.oneOf("processingType", s.ProcessingType, processingTypes)
+	return v.err("sale")
+}
+
+func (p *UpdatePlan) Validate() error {
+	var v validator
+	v.required("planCode", p.PlanCode)
+	v.length("planCode", p.PlanCode, 1, 25)
+	return v.err("updatePlan")
+}
+
+func (s *UpdateSubscription) Validate() error {
+	var v validator
+	v.required("subscriptionId", s.SubscriptionId)
+	v.digits("subscriptionId", s.SubscriptionId, 19)
+	v.maxLength("planCode", s.PlanCode, 25)
+	if s.BillToAddress != nil {
+		v.address("billToAddress", *s.BillToAddress)
+	}
+	if s.Card != nil {
+		v.card("card", *s.Card)
+	}
+	if s.Token != nil {
+		v.token("token", *s.Token)
+	}
+	if s.Paypage != nil {
+		v.paypage("paypage", *s.Paypage)
+	}
+	return v.err("updateSubscription")
+}
+
+func (vd *Void) Validate() error {
+	var v validator
+	v.attributes(vd.Id, vd.ReportGroup, "")
+	v.litleTxnId(vd.LitleTxnId)
+	return v.err("void")
+}
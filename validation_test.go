@@ -0,0 +1,98 @@
+package worldpay
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidate(t *testing.T) {
+	checkNum := "1234"
+	amount := 100
+
+	valid := []validatable{
+		&Authorization{Id: "1", OrderId: "1", Amount: 100, OrderSource: "ecommerce", Card: Card{Type: "VI", Number: "4005550000081019", ExpDate: "1210"}},
+		&AuthReversal{LitleTxnId: "82924701437133501", Amount: &amount},
+		&CancelSubscription{SubscriptionId: "123"},
+		&Capture{LitleTxnId: "82924701437133501", Amount: 100},
+		&CaptureGivenAuth{OrderId: "1", Amount: 100, OrderSource: "ecommerce", AuthInformation: AuthInformation{AuthDate: "2024-01-01", AuthCode: "123456"}, Card: Card{Type: "MC", Number: "5112010000000003", ExpDate: "0125"}},
+		&CreatePlan{PlanCode: "Monthly", Name: "Monthly", IntervalType: "MONTHLY", Amount: 100},
+		&Credit{LitleTxnId: "82924701437133501"},
+		&Credit{OrderId: "1", Amount: &amount, OrderSource: "ecommerce", Token: &Token{LitleToken: "1111222233334444"}},
+		&EcheckCredit{LitleTxnId: "82924701437133501", Amount: 100},
+		&EcheckSale{OrderId: "1", Amount: 100, OrderSource: "telephone", Echeck: Echeck{AccType: "Checking", AccNum: "1234567890", RoutingNum: "011075150", CheckNum: &checkNum}},
+		&EcheckVoid{LitleTxnId: "82924701437133501"},
+		&ForceCapture{OrderId: "1", Amount: 100, OrderSource: "ecommerce", Card: Card{Type: "VI", Number: "4005550000081019", ExpDate: "1210"}},
+		&QueryTransaction{OrigId: "1", OrigActionType: "A"},
+		&RegisterTokenRequest{OrderId: "1", AccountNumber: "4005550000081019"},
+		&Sale{OrderId: "1", Amount: 100, OrderSource: "ecommerce", Paypage: &Paypage{PaypageRegistrationId: "abc"}},
+		&UpdatePlan{PlanCode: "Monthly"},
+		&UpdateSubscription{SubscriptionId: "123"},
+		&Void{LitleTxnId: "82924701437133501"},
+	}
+	for _, v := range valid {
+		assert.Nil(t, v.Validate(), "%T", v)
+	}
+}
+
+func TestValidateViolations(t *testing.T) {
+	sale := &Sale{
+		Id:          "1",
+		OrderId:     strings.Repeat("1", 26),
+		Amount:      -1,
+		OrderSource: "web",
+		Card: Card{
+			Type:    "FOO",
+			Number:  "4005550000081019",
+			ExpDate: "1310",
+		},
+		ProcessingType: "initialCOF",
+	}
+
+	err := sale.Validate()
+
+	var validationErr *ValidationError
+	assert.True(t, errors.As(err, &validationErr))
+	assert.Equal(t, "sale", validationErr.Transaction)
+	assert.Equal(t, []FieldError{
+		{Field: "orderId", Message: "must be at most 25 characters"},
+		{Field: "amount", Message: "must be between 0 and 999999999999"},
+		{Field: "orderSource", Message: "must be one of ecommerce, installment, mailorder, recurring, retail, telephone, 3dsAuthenticated, 3dsAttempted, recurringtel, echeckppd, applepay, androidpay, samsungpay, visacheckout, masterpass, item"},
+		{Field: "card.type", Message: "must be one of MC, VI, AX, DC, DI, PP, JC, BL, EC, GC"},
+		{Field: "card.expDate", Message: "must be a MMYY date"},
+	}, validationErr.Errors)
+	assert.Contains(t, err.Error(), "Invalid sale: orderId must be at most 25 characters; amount must be")
+
+	echeck := &EcheckSale{OrderId: "1", OrderSource: "telephone", Echeck: Echeck{AccType: "Money Market", AccNum: "1234567890", RoutingNum: "1234"}}
+	assert.True(t, errors.As(echeck.Validate(), &validationErr))
+	assert.Equal(t, []FieldError{
+		{Field: "echeck.accType", Message: "must be one of Checking, Savings, Corporate, Corp Savings"},
+		{Field: "echeck.routingNum", Message: "must be 9 digits"},
+	}, validationErr.Errors)
+
+	void := &Void{}
+	assert.True(t, errors.As(void.Validate(), &validationErr))
+	assert.Equal(t, []FieldError{{Field: "litleTxnId", Message: "is required"}}, validationErr.Errors)
+}
+
+func TestClientValidation(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Error("invalid request was sent")
+	}))
+	defer server.Close()
+
+	c, _ := NewClient(login, password, server.URL, WithValidation())
+
+	res, err := c.Void(context.Background(), merchantId, &Void{Id: "1"})
+	assert.Nil(t, res)
+	assert.IsType(t, &ValidationError{}, err)
+
+	c, _ = NewClient(login, password, apiBase)
+	_, err = c.GetTransactionXml(merchantId, &Void{Id: "1"})
+	assert.Nil(t, err)
+}
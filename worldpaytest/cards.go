@@ -0,0 +1,55 @@
+package worldpaytest
+
+import "github.com/anedot/worldpay-cnp"
+
+// CardResult is the outcome the fake gives a card number, mimicking the
+// sandbox's test cards.
+type CardResult struct {
+	Response             string
+	AuthCode             string
+	AvsResult            string
+	CardValidationResult string
+	// NewCard, when set, is reported as the account updater's new card info.
+	NewCard *worldpay.Card
+}
+
+// DefaultCards maps the sandbox's magic card numbers to their outcome. Each
+// Server starts with a copy in its Cards field. Numbers not listed are
+// approved.
+var DefaultCards = map[string]CardResult{
+	"4457010000000009": {Response: "000", AuthCode: "11111", AvsResult: "01", CardValidationResult: "M"},
+	"5112010000000003": {Response: "000", AuthCode: "22222", AvsResult: "02", CardValidationResult: "M"},
+	"6011010000000003": {Response: "000", AuthCode: "33333", AvsResult: "03", CardValidationResult: "M"},
+	"375001000000005":  {Response: "000", AuthCode: "44444", AvsResult: "12"},
+	"4100521234567000": {Response: "000", AuthCode: "11111", CardValidationResult: "P"},
+	"4100117890123000": {Response: "000", AuthCode: "11111", NewCard: &worldpay.Card{Type: "VI", Number: "4457000300000007"}},
+	"4200410886320101": {Response: "101", AvsResult: "10"},
+	"4457010100000008": {Response: "110", AvsResult: "34", CardValidationResult: "P"},
+	"6011010100000002": {Response: "120", AvsResult: "34", CardValidationResult: "P"},
+	"5112010100000002": {Response: "301", AvsResult: "34", CardValidationResult: "N"},
+	"375001010000003":  {Response: "303", AvsResult: "34"},
+}
+
+var approved = CardResult{Response: "000", AuthCode: "123457"}
+
+// cardType derives the method of payment from the leading digits of a card
+// number, as the gateway reports it for registered tokens.
+func cardType(number string) string {
+	switch {
+	case len(number) == 0:
+		return ""
+	case number[0] == '4':
+		return "VI"
+	case number[0] == '5' || number[0] == '2':
+		return "MC"
+	case len(number) > 1 && (number[:2] == "34" || number[:2] == "37"):
+		return "AX"
+	case number[0] == '6':
+		return "DI"
+	case len(number) > 1 && (number[:2] == "30" || number[:2] == "36" || number[:2] == "38"):
+		return "DC"
+	case len(number) > 1 && number[:2] == "35":
+		return "JC"
+	}
+	return ""
+}
@@ -0,0 +1,230 @@
+package worldpaytest
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"regexp"
+	"strings"
+	"sync"
+
+	"github.com/anedot/worldpay-cnp"
+)
+
+// Mode selects whether a Recorder records or replays.
+type Mode int
+
+const (
+	// Replay serves responses from the cassette without sending requests.
+	Replay Mode = iota
+	// Record sends requests with the Recorder's Transport and saves each
+	// exchange to the cassette.
+	Record
+)
+
+type (
+	// Recorder is an http.RoundTripper that records request and response
+	// pairs to a cassette file, and replays them, so that tests can run
+	// deterministically against traffic captured once from the sandbox.
+	// Credentials, account numbers and tokens are masked before anything is
+	// written. Replayed requests are matched on their transaction type and Id.
+	Recorder struct {
+		Path string
+		Mode Mode
+		// Transport sends requests while recording. It defaults to
+		// http.DefaultTransport.
+		Transport http.RoundTripper
+
+		mu       sync.Mutex
+		cassette Cassette
+		used     []bool
+	}
+
+	Cassette struct {
+		Interactions []Interaction `json:"interactions"`
+	}
+
+	// Interaction is a single recorded exchange.
+	Interaction struct {
+		// Transaction is the element name of the transaction, e.g. "sale".
+		Transaction string   `json:"transaction"`
+		Id          string   `json:"id"`
+		Request     string   `json:"request"`
+		Response    Response `json:"response"`
+	}
+
+	Response struct {
+		StatusCode  int    `json:"statusCode"`
+		ContentType string `json:"contentType,omitempty"`
+		Body        string `json:"body"`
+	}
+)
+
+// login matches the login, which logs keep but cassettes, being committed,
+// do not. Everything else is masked with worldpay.MaskSensitive.
+var login = regexp.MustCompile(`<user>[^<]*</`)
+
+// NewRecorder returns a Recorder for the cassette at path. In Replay mode
+// the cassette is loaded, and must exist.
+func NewRecorder(path string, mode Mode) (*Recorder, error) {
+	r := &Recorder{
+		Path: path,
+		Mode: mode,
+	}
+
+	if mode == Replay {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		if err := json.Unmarshal(data, &r.cassette); err != nil {
+			return nil, err
+		}
+		r.used = make([]bool, len(r.cassette.Interactions))
+	}
+
+	return r, nil
+}
+
+// Client returns an HTTP client that sends requests through the Recorder,
+// for use with worldpay.WithHTTPClient.
+func (r *Recorder) Client() *http.Client {
+	return &http.Client{Transport: r}
+}
+
+func (r *Recorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	var body []byte
+	if req.Body != nil {
+		var err error
+		body, err = io.ReadAll(req.Body)
+		req.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+		req.Body = io.NopCloser(bytes.NewReader(body))
+	}
+
+	transaction, id := transactionKey(body)
+
+	if r.Mode == Replay {
+		return r.replay(req, transaction, id)
+	}
+	return r.record(req, body, transaction, id)
+}
+
+func (r *Recorder) replay(req *http.Request, transaction, id string) (*http.Response, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for i, interaction := range r.cassette.Interactions {
+		if r.used[i] || interaction.Transaction != transaction || interaction.Id != id {
+			continue
+		}
+		r.used[i] = true
+		return interaction.Response.httpResponse(req), nil
+	}
+
+	return nil, fmt.Errorf("No recorded %s with id %q in %s", transaction, id, r.Path)
+}
+
+func (r *Recorder) record(req *http.Request, body []byte, transaction, id string) (*http.Response, error) {
+	transport := r.Transport
+	if transport == nil {
+		transport = http.DefaultTransport
+	}
+
+	resp, err := transport.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(respBody))
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.cassette.Interactions = append(r.cassette.Interactions, Interaction{
+		Transaction: transaction,
+		Id:          id,
+		Request:     redact(body),
+		Response: Response{
+			StatusCode:  resp.StatusCode,
+			ContentType: resp.Header.Get("Content-Type"),
+			Body:        redact(respBody),
+		},
+	})
+
+	// The cassette is saved after every exchange so that nothing is lost
+	// when a test fails part way through.
+	data, err := json.MarshalIndent(r.cassette, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	if err := os.WriteFile(r.Path, data, 0600); err != nil {
+		return nil, err
+	}
+
+	return resp, nil
+}
+
+func (r Response) httpResponse(req *http.Request) *http.Response {
+	header := make(http.Header)
+	if r.ContentType != "" {
+		header.Set("Content-Type", r.ContentType)
+	}
+
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", r.StatusCode, http.StatusText(r.StatusCode)),
+		StatusCode:    r.StatusCode,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          io.NopCloser(strings.NewReader(r.Body)),
+		ContentLength: int64(len(r.Body)),
+		Request:       req,
+	}
+}
+
+// transactionKey returns the element name and id attribute of the
+// transaction in an online request.
+func transactionKey(body []byte) (string, string) {
+	dec := xml.NewDecoder(bytes.NewReader(body))
+
+	depth := 0
+	for {
+		tok, err := dec.Token()
+		if err != nil {
+			return "", ""
+		}
+
+		switch t := tok.(type) {
+		case xml.StartElement:
+			depth++
+			if depth == 2 && t.Name.Local != "authentication" {
+				for _, attr := range t.Attr {
+					if attr.Name.Local == "id" {
+						return t.Name.Local, attr.Value
+					}
+				}
+				return t.Name.Local, ""
+			}
+		case xml.EndElement:
+			depth--
+		}
+	}
+}
+
+func redact(body []byte) string {
+	body = login.ReplaceAll(body, []byte("<user>********</"))
+	return string(worldpay.MaskSensitive(body))
+}
@@ -0,0 +1,96 @@
+package worldpaytest
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/anedot/worldpay-cnp"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRecorder(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "sale.json")
+
+	sale := &worldpay.Sale{
+		Id:          "1",
+		OrderId:     "5234234",
+		Amount:      40000,
+		OrderSource: "ecommerce",
+		Card:        worldpay.Card{Type: "VI", Number: "4100117890123000", ExpDate: "1210", CardValidationNum: "555"},
+	}
+
+	s := NewServer()
+	recorder, err := NewRecorder(path, Record)
+	assert.Nil(t, err)
+
+	c, _ := worldpay.NewClient(Login, Password, s.URL, worldpay.WithHTTPClient(recorder.Client()))
+	recorded, err := c.Sale(context.Background(), "100", sale)
+	assert.Nil(t, err)
+	s.Close()
+
+	data, _ := os.ReadFile(path)
+	assert.Contains(t, string(data), `"transaction": "sale"`)
+	assert.Contains(t, string(data), "\\u003cpassword\\u003e********\\u003c/password\\u003e")
+	assert.Contains(t, string(data), "\\u003cuser\\u003e********\\u003c/user\\u003e")
+	assert.Contains(t, string(data), "************3000")
+	assert.NotContains(t, string(data), "4100117890123000")
+	assert.NotContains(t, string(data), "\\u003e"+Password+"\\u003c")
+	assert.NotContains(t, string(data), "\\u003e"+Login+"\\u003c")
+	assert.NotContains(t, string(data), "555")
+
+	recorder, err = NewRecorder(path, Replay)
+	assert.Nil(t, err)
+
+	c, _ = worldpay.NewClient(Login, Password, s.URL, worldpay.WithHTTPClient(recorder.Client()))
+	replayed, err := c.Sale(context.Background(), "100", sale)
+	assert.Nil(t, err)
+	assert.Equal(t, recorded.SaleResponse.LitleTxnId, replayed.SaleResponse.LitleTxnId)
+	assert.Equal(t, "************3000", replayed.SaleResponse.AccountUpdater.OriginalCardInfo.Number)
+
+	// Each interaction is replayed once.
+	_, err = c.Sale(context.Background(), "100", sale)
+	assert.IsType(t, &worldpay.TransportError{}, err)
+
+	_, err = c.Void(context.Background(), "100", &worldpay.Void{Id: "1", LitleTxnId: "1"})
+	assert.ErrorContains(t, err, `No recorded void with id "1"`)
+}
+
+func TestRecorderTokens(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "token.json")
+
+	s := NewServer()
+	defer s.Close()
+	recorder, err := NewRecorder(path, Record)
+	assert.Nil(t, err)
+
+	c, _ := worldpay.NewClient(Login, Password, s.URL, worldpay.WithHTTPClient(recorder.Client()))
+	res, err := c.RegisterToken(context.Background(), "100", &worldpay.RegisterTokenRequest{Id: "1", OrderId: "1", AccountNumber: "4457119922390123"})
+	assert.Nil(t, err)
+	token := res.RegisterTokenResponse.LitleToken
+
+	_, err = c.Sale(context.Background(), "100", &worldpay.Sale{
+		Id:          "2",
+		OrderId:     "2",
+		Amount:      100,
+		OrderSource: "ecommerce",
+		Token:       &worldpay.Token{LitleToken: token, ExpDate: "1210"},
+	})
+	assert.Nil(t, err)
+
+	_, err = c.RegisterToken(context.Background(), "100", &worldpay.RegisterTokenRequest{Id: "3", OrderId: "3", PaypageRegistrationId: "cDZJcmd1VjNlYXNaSlRMTGpocVZQY1NWVXE4Z W5UTko4NU9GK3FnOWtUZ1pxOVh2NA=="})
+	assert.Nil(t, err)
+
+	data, _ := os.ReadFile(path)
+	assert.NotEmpty(t, token)
+	assert.NotContains(t, string(data), token)
+	assert.Contains(t, string(data), token[len(token)-4:]+"\\u003c/litleToken")
+	assert.NotContains(t, string(data), "cDZJcmd1VjNl")
+	assert.NotContains(t, string(data), "4457119922390123")
+}
+
+func TestNewRecorderMissingCassette(t *testing.T) {
+	_, err := NewRecorder(filepath.Join(t.TempDir(), "missing.json"), Replay)
+	assert.NotNil(t, err)
+}
@@ -0,0 +1,621 @@
+// Package worldpaytest provides a fake cnpAPI server for testing code that
+// uses the worldpay client without reaching the sandbox.
+package worldpaytest
+
+import (
+	"bytes"
+	"encoding/xml"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/anedot/worldpay-cnp"
+)
+
+const (
+	Login    = "username"
+	Password = "password"
+
+	// firstLitleTxnId is where assigned litleTxnIds start, so they look like
+	// the gateway's.
+	firstLitleTxnId = 82924701437133500
+)
+
+type (
+	// Responder returns the response to an online request, or nil to leave
+	// it to the next responder.
+	Responder func(req *worldpay.LitleOnlineRequest) *worldpay.LitleOnlineResponse
+
+	// Server is an httptest server that answers litleOnlineRequests, and the
+	// 12.x cnpOnlineRequests, like the sandbox. Requests are validated against the schema, card numbers in
+	// Cards decide the outcome of authorizations and sales, and every other
+	// transaction is approved.
+	Server struct {
+		*httptest.Server
+
+		// Cards maps card numbers to the outcome of authorizations and sales.
+		Cards map[string]CardResult
+
+		mu         sync.Mutex
+		responders []Responder
+		history    []exchange
+		litleTxnId int64
+		ledger     *ledger
+	}
+
+	exchange struct {
+		payload  interface{}
+		response *worldpay.LitleOnlineResponse
+	}
+)
+
+// NewServer starts a fake server. Callers should Close it when done.
+func NewServer() *Server {
+	s := &Server{
+		Cards:      make(map[string]CardResult, len(DefaultCards)),
+		litleTxnId: firstLitleTxnId,
+	}
+	for number, result := range DefaultCards {
+		s.Cards[number] = result
+	}
+	s.Server = httptest.NewServer(http.HandlerFunc(s.serveHTTP))
+	return s
+}
+
+// Client returns a worldpay client that sends requests to the server.
+func (s *Server) Client(opts ...worldpay.Option) *worldpay.Client {
+	opts = append([]worldpay.Option{worldpay.WithHTTPClient(s.Server.Client())}, opts...)
+	c, _ := worldpay.NewClient(Login, Password, s.URL, opts...)
+	return c
+}
+
+// Handle scripts responses. Responders are consulted before the built-in
+// behaviour, most recently added first.
+func (s *Server) Handle(r Responder) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.responders = append(s.responders, r)
+}
+
+// Requests returns the requests received so far.
+func (s *Server) Requests() []interface{} {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	payloads := make([]interface{}, len(s.history))
+	for i, e := range s.history {
+		payloads[i] = e.payload
+	}
+	return payloads
+}
+
+func (s *Server) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	// Both litleOnlineRequest and the 12.x cnpOnlineRequest decode into the
+	// same type, and are answered in the schema version they were sent in.
+	var req worldpay.LitleOnlineRequest
+	if err := worldpay.NewSchemaDecoder(bytes.NewReader(body)).Decode(&req); err != nil {
+		writeResponse(w, schemaError(&req, err.Error()))
+		return
+	}
+
+	writeResponse(w, s.respond(&req))
+}
+
+func (s *Server) respond(req *worldpay.LitleOnlineRequest) *worldpay.LitleOnlineResponse {
+	payload := transaction(req)
+	if payload == nil {
+		return schemaError(req, "missing transaction")
+	}
+
+	// Responders run without the lock held, so that they may call back
+	// into the server, e.g. for its Requests.
+	s.mu.Lock()
+	responders := append([]Responder(nil), s.responders...)
+	s.mu.Unlock()
+
+	var res *worldpay.LitleOnlineResponse
+	for i := len(responders) - 1; i >= 0 && res == nil; i-- {
+		res = responders[i](req)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if res == nil {
+		res = s.defaultResponse(req, payload)
+	}
+	if res.Version == "" {
+		res.Version = req.Version
+		res.XmlNS = req.XmlNamespace
+	}
+
+	s.history = append(s.history, exchange{payload: payload, response: res})
+	return res
+}
+
+func (s *Server) defaultResponse(req *worldpay.LitleOnlineRequest, payload interface{}) *worldpay.LitleOnlineResponse {
+	if v, ok := payload.(interface{ Validate() error }); ok {
+		if err := v.Validate(); err != nil {
+			return schemaError(req, err.Error())
+		}
+	}
+
+	res := &worldpay.LitleOnlineResponse{Response: "0", Message: "Valid Format"}
+
+	// Transactions that follow up on an earlier one are approved unless the
+	// ledger, when tracked, rules them out.
+	code := "000"
+	if s.ledger != nil {
+		code = s.ledger.check(payload)
+	}
+
+	switch p := payload.(type) {
+	case *worldpay.Authorization:
+		result := s.paymentResult(p.Card, p.Token, p.Paypage)
+		res.AuthorizationResponse = &worldpay.AuthorizationResponse{
+			Id:             p.Id,
+			ReportGroup:    p.ReportGroup,
+			CustomerId:     p.CustomerId,
+			LitleTxnId:     s.nextLitleTxnId(),
+			OrderId:        p.OrderId,
+			Response:       result.Response,
+			ResponseTime:   responseTime(),
+			PostDate:       postDate(),
+			Message:        message(result.Response),
+			AuthCode:       result.AuthCode,
+			FraudResult:    fraudResult(result),
+			AccountUpdater: accountUpdater(p.Card, result),
+		}
+		if result.Response == "000" {
+			res.AuthorizationResponse.ApprovedAmount = strconv.Itoa(p.Amount)
+		}
+	case *worldpay.AuthReversal:
+		res.AuthReversalResponse = &worldpay.AuthReversalResponse{
+			Id:           p.Id,
+			ReportGroup:  p.ReportGroup,
+			CustomerId:   p.CustomerId,
+			LitleTxnId:   s.nextLitleTxnId(),
+			Response:     code,
+			ResponseTime: responseTime(),
+			PostDate:     postDate(),
+			Message:      message(code),
+		}
+	case *worldpay.CancelSubscription:
+		res.CancelSubscriptionResponse = &worldpay.CancelSubscriptionResponse{
+			LitleTxnId:     s.nextLitleTxnId(),
+			Response:       "000",
+			Message:        message("000"),
+			ResponseTime:   responseTime(),
+			SubscriptionId: p.SubscriptionId,
+		}
+	case *worldpay.Capture:
+		res.CaptureResponse = &worldpay.CaptureResponse{
+			Id:           p.Id,
+			ReportGroup:  p.ReportGroup,
+			CustomerId:   p.CustomerId,
+			LitleTxnId:   s.nextLitleTxnId(),
+			Response:     code,
+			ResponseTime: responseTime(),
+			PostDate:     postDate(),
+			Message:      message(code),
+		}
+	case *worldpay.CaptureGivenAuth:
+		res.CaptureGivenAuthResponse = &worldpay.CaptureGivenAuthResponse{
+			Id:           p.Id,
+			ReportGroup:  p.ReportGroup,
+			CustomerId:   p.CustomerId,
+			LitleTxnId:   s.nextLitleTxnId(),
+			OrderId:      p.OrderId,
+			Response:     "000",
+			ResponseTime: responseTime(),
+			PostDate:     postDate(),
+			Message:      message("000"),
+		}
+	case *worldpay.CreatePlan:
+		res.CreatePlanResponse = &worldpay.CreatePlanResponse{
+			LitleTxnId:   s.nextLitleTxnId(),
+			Response:     "000",
+			Message:      message("000"),
+			ResponseTime: responseTime(),
+			PlanCode:     p.PlanCode,
+		}
+	case *worldpay.Credit:
+		res.CreditResponse = &worldpay.CreditResponse{
+			Id:           p.Id,
+			ReportGroup:  p.ReportGroup,
+			LitleTxnId:   s.nextLitleTxnId(),
+			Response:     code,
+			ResponseTime: responseTime(),
+			Message:      message(code),
+		}
+	case *worldpay.EcheckCredit:
+		res.EcheckCreditResponse = &worldpay.EcheckCreditResponse{
+			Id:           p.Id,
+			ReportGroup:  p.ReportGroup,
+			CustomerId:   p.CustomerId,
+			LitleTxnId:   s.nextLitleTxnId(),
+			Response:     code,
+			ResponseTime: responseTime(),
+			Message:      message(code),
+		}
+	case *worldpay.EcheckSale:
+		res.EcheckSaleResponse = &worldpay.EcheckSaleResponse{
+			Id:           p.Id,
+			ReportGroup:  p.ReportGroup,
+			CustomerId:   p.CustomerId,
+			LitleTxnId:   s.nextLitleTxnId(),
+			Response:     "000",
+			ResponseTime: responseTime(),
+			Message:      message("000"),
+			PostDate:     postDate(),
+		}
+	case *worldpay.EcheckVoid:
+		res.EcheckVoidResponse = &worldpay.EcheckVoidResponse{
+			Id:           p.Id,
+			ReportGroup:  p.ReportGroup,
+			LitleTxnId:   s.nextLitleTxnId(),
+			Response:     code,
+			ResponseTime: responseTime(),
+			Message:      message(code),
+			PostDate:     postDate(),
+		}
+	case *worldpay.ForceCapture:
+		res.ForceCaptureResponse = &worldpay.ForceCaptureResponse{
+			Id:           p.Id,
+			ReportGroup:  p.ReportGroup,
+			CustomerId:   p.CustomerId,
+			LitleTxnId:   s.nextLitleTxnId(),
+			OrderId:      p.OrderId,
+			Response:     "000",
+			ResponseTime: responseTime(),
+			PostDate:     postDate(),
+			Message:      message("000"),
+		}
+	case *worldpay.QueryTransaction:
+		res.QueryTransactionResponse = s.queryTransaction(p)
+	case *worldpay.RegisterTokenRequest:
+		res.RegisterTokenResponse = s.registerToken(p)
+	case *worldpay.Sale:
+		result := s.paymentResult(p.Card, p.Token, p.Paypage)
+		res.SaleResponse = &worldpay.SaleResponse{
+			Id:             p.Id,
+			ReportGroup:    p.ReportGroup,
+			CustomerId:     p.CustomerId,
+			LitleTxnId:     s.nextLitleTxnId(),
+			Response:       result.Response,
+			OrderId:        p.OrderId,
+			ResponseTime:   responseTime(),
+			PostDate:       postDate(),
+			Message:        message(result.Response),
+			AuthCode:       result.AuthCode,
+			FraudResult:    fraudResult(result),
+			AccountUpdater: accountUpdater(p.Card, result),
+		}
+		if p.RecurringRequest != nil && result.Response == "000" {
+			res.SaleResponse.RecurringResponse = &worldpay.RecurringResponse{
+				SubscriptionId:  s.nextLitleTxnId(),
+				ResponseCode:    "000",
+				ResponseMessage: message("000"),
+			}
+		}
+	case *worldpay.UpdatePlan:
+		res.UpdatePlanResponse = &worldpay.UpdatePlanResponse{
+			LitleTxnId:   s.nextLitleTxnId(),
+			Response:     "000",
+			Message:      message("000"),
+			ResponseTime: responseTime(),
+			PlanCode:     p.PlanCode,
+		}
+	case *worldpay.UpdateSubscription:
+		res.UpdateSubscriptionResponse = &worldpay.UpdateSubscriptionResponse{
+			LitleTxnId:     s.nextLitleTxnId(),
+			Response:       "000",
+			Message:        message("000"),
+			ResponseTime:   responseTime(),
+			SubscriptionId: p.SubscriptionId,
+		}
+	case *worldpay.Void:
+		res.VoidResponse = &worldpay.VoidResponse{
+			Id:           p.Id,
+			ReportGroup:  p.ReportGroup,
+			LitleTxnId:   s.nextLitleTxnId(),
+			Response:     code,
+			ResponseTime: responseTime(),
+			PostDate:     postDate(),
+			Message:      message(code),
+		}
+	}
+
+	if s.ledger != nil {
+		s.ledger.record(payload, res)
+	}
+
+	return res
+}
+
+// paymentResult looks up the outcome of a card payment. Tokens and paypage
+// registrations are approved.
+func (s *Server) paymentResult(card worldpay.Card, token *worldpay.Token, paypage *worldpay.Paypage) CardResult {
+	if token != nil || paypage != nil {
+		return approved
+	}
+	if r, ok := s.Cards[card.Number]; ok {
+		return r
+	}
+	return approved
+}
+
+func (s *Server) registerToken(p *worldpay.RegisterTokenRequest) *worldpay.RegisterTokenResponse {
+	res := &worldpay.RegisterTokenResponse{
+		Id:           p.Id,
+		ReportGroup:  p.ReportGroup,
+		CustomerId:   p.CustomerId,
+		LitleTxnId:   s.nextLitleTxnId(),
+		OrderId:      p.OrderId,
+		Response:     "801",
+		Message:      message("801"),
+		ResponseTime: responseTime(),
+	}
+
+	switch {
+	case p.AccountNumber != "":
+		res.LitleToken = token(p.AccountNumber)
+		res.Bin = p.AccountNumber[:6]
+		res.Type = cardType(p.AccountNumber)
+	case p.EcheckForToken != nil:
+		res.LitleToken = token(p.EcheckForToken.AccNum)
+		res.EcheckAccountSuffix = suffix(p.EcheckForToken.AccNum, 3)
+	default:
+		res.LitleToken = token(p.PaypageRegistrationId)
+	}
+
+	for _, e := range s.history {
+		if prev, ok := e.response.TransactionResponse().(*worldpay.RegisterTokenResponse); ok && prev.LitleToken == res.LitleToken {
+			res.Response = "802"
+			res.Message = message("802")
+			break
+		}
+	}
+
+	return res
+}
+
+// queryTransaction finds earlier transactions by their Id and action type.
+func (s *Server) queryTransaction(p *worldpay.QueryTransaction) *worldpay.QueryTransactionResponse {
+	res := &worldpay.QueryTransactionResponse{
+		Id:           p.Id,
+		ReportGroup:  p.ReportGroup,
+		CustomerId:   p.CustomerId,
+		ResponseTime: responseTime(),
+	}
+
+	for _, e := range s.history {
+		if transactionId(e.payload) != p.OrigId || actionType(e.payload) != p.OrigActionType {
+			continue
+		}
+		switch r := e.response.TransactionResponse().(type) {
+		case *worldpay.AuthorizationResponse:
+			res.Results.AuthorizationResponses = append(res.Results.AuthorizationResponses, r)
+		case *worldpay.AuthReversalResponse:
+			res.Results.AuthReversalResponses = append(res.Results.AuthReversalResponses, r)
+		case *worldpay.CaptureResponse:
+			res.Results.CaptureResponses = append(res.Results.CaptureResponses, r)
+		case *worldpay.CaptureGivenAuthResponse:
+			res.Results.CaptureGivenAuthResponses = append(res.Results.CaptureGivenAuthResponses, r)
+		case *worldpay.CreditResponse:
+			res.Results.CreditResponses = append(res.Results.CreditResponses, r)
+		case *worldpay.EcheckCreditResponse:
+			res.Results.EcheckCreditResponses = append(res.Results.EcheckCreditResponses, r)
+		case *worldpay.EcheckSaleResponse:
+			res.Results.EcheckSaleResponses = append(res.Results.EcheckSaleResponses, r)
+		case *worldpay.EcheckVoidResponse:
+			res.Results.EcheckVoidResponses = append(res.Results.EcheckVoidResponses, r)
+		case *worldpay.ForceCaptureResponse:
+			res.Results.ForceCaptureResponses = append(res.Results.ForceCaptureResponses, r)
+		case *worldpay.SaleResponse:
+			res.Results.SaleResponses = append(res.Results.SaleResponses, r)
+		case *worldpay.VoidResponse:
+			res.Results.VoidResponses = append(res.Results.VoidResponses, r)
+		default:
+			continue
+		}
+		res.MatchCount++
+	}
+
+	res.Response = "151"
+	if res.MatchCount > 0 {
+		res.Response = "150"
+	}
+	res.Message = message(res.Response)
+
+	return res
+}
+
+func (s *Server) nextLitleTxnId() string {
+	s.litleTxnId++
+	return strconv.FormatInt(s.litleTxnId, 10)
+}
+
+func transaction(req *worldpay.LitleOnlineRequest) interface{} {
+	switch {
+	case req.Authorization != nil:
+		return req.Authorization
+	case req.AuthReversal != nil:
+		return req.AuthReversal
+	case req.CancelSubscription != nil:
+		return req.CancelSubscription
+	case req.Capture != nil:
+		return req.Capture
+	case req.CaptureGivenAuth != nil:
+		return req.CaptureGivenAuth
+	case req.CreatePlan != nil:
+		return req.CreatePlan
+	case req.Credit != nil:
+		return req.Credit
+	case req.EcheckCredit != nil:
+		return req.EcheckCredit
+	case req.EcheckSale != nil:
+		return req.EcheckSale
+	case req.EcheckVoid != nil:
+		return req.EcheckVoid
+	case req.ForceCapture != nil:
+		return req.ForceCapture
+	case req.QueryTransaction != nil:
+		return req.QueryTransaction
+	case req.RegisterToken != nil:
+		return req.RegisterToken
+	case req.Sale != nil:
+		return req.Sale
+	case req.UpdatePlan != nil:
+		return req.UpdatePlan
+	case req.UpdateSubscription != nil:
+		return req.UpdateSubscription
+	case req.Void != nil:
+		return req.Void
+	}
+	return nil
+}
+
+func transactionId(payload interface{}) string {
+	switch p := payload.(type) {
+	case *worldpay.Authorization:
+		return p.Id
+	case *worldpay.AuthReversal:
+		return p.Id
+	case *worldpay.Capture:
+		return p.Id
+	case *worldpay.CaptureGivenAuth:
+		return p.Id
+	case *worldpay.Credit:
+		return p.Id
+	case *worldpay.EcheckCredit:
+		return p.Id
+	case *worldpay.EcheckSale:
+		return p.Id
+	case *worldpay.EcheckVoid:
+		return p.Id
+	case *worldpay.ForceCapture:
+		return p.Id
+	case *worldpay.Sale:
+		return p.Id
+	case *worldpay.Void:
+		return p.Id
+	}
+	return ""
+}
+
+// actionType returns the origActionType a queryTransaction uses to find the
+// transaction.
+func actionType(payload interface{}) string {
+	switch payload.(type) {
+	case *worldpay.Authorization:
+		return "A"
+	case *worldpay.AuthReversal:
+		return "AR"
+	case *worldpay.Capture:
+		return "D"
+	case *worldpay.CaptureGivenAuth:
+		return "CG"
+	case *worldpay.Credit:
+		return "R"
+	case *worldpay.EcheckCredit:
+		return "ER"
+	case *worldpay.EcheckSale:
+		return "ES"
+	case *worldpay.EcheckVoid:
+		return "EV"
+	case *worldpay.ForceCapture:
+		return "F"
+	case *worldpay.Sale:
+		return "S"
+	case *worldpay.Void:
+		return "V"
+	}
+	return ""
+}
+
+func schemaError(req *worldpay.LitleOnlineRequest, reason string) *worldpay.LitleOnlineResponse {
+	return &worldpay.LitleOnlineResponse{
+		Version:  req.Version,
+		XmlNS:    req.XmlNamespace,
+		Response: "1",
+		Message:  "Error validating xml data against the schema: " + reason,
+	}
+}
+
+func writeResponse(w http.ResponseWriter, res *worldpay.LitleOnlineResponse) {
+	body, err := worldpay.MarshalSchema(res, worldpay.SchemaVersion(res.Version))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/xml")
+	io.WriteString(w, xml.Header)
+	w.Write(body)
+}
+
+func message(code string) string {
+	rc, _ := worldpay.LookupResponseCode(code)
+	return rc.Message
+}
+
+func fraudResult(result CardResult) *worldpay.FraudResult {
+	return &worldpay.FraudResult{
+		AvsResult:            result.AvsResult,
+		CardValidationResult: result.CardValidationResult,
+	}
+}
+
+func accountUpdater(card worldpay.Card, result CardResult) *worldpay.AccountUpdater {
+	if result.NewCard == nil {
+		return nil
+	}
+
+	newCard := *result.NewCard
+	if newCard.ExpDate == "" {
+		newCard.ExpDate = card.ExpDate
+	}
+	return &worldpay.AccountUpdater{
+		OriginalCardInfo: worldpay.Card{Type: card.Type, Number: card.Number, ExpDate: card.ExpDate},
+		NewCardInfo:      newCard,
+	}
+}
+
+// token derives a stable token from an account number, so that registering
+// the same account twice returns the same token.
+func token(account string) string {
+	digits := make([]byte, 0, 12)
+	for i := len(account) - 1; i >= 0 && len(digits) < 12; i-- {
+		digits = append(digits, '0'+account[i]%10)
+	}
+	for len(digits) < 12 {
+		digits = append(digits, '0')
+	}
+	return "1111" + string(digits)
+}
+
+func suffix(s string, n int) string {
+	if len(s) < n {
+		return s
+	}
+	return s[len(s)-n:]
+}
+
+func responseTime() string {
+	return time.Now().UTC().Format("2006-01-02T15:04:05")
+}
+
+func postDate() string {
+	return time.Now().UTC().Format("2006-01-02")
+}
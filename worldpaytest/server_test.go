@@ -0,0 +1,247 @@
+package worldpaytest
+
+import (
+	"context"
+	"testing"
+
+	"github.com/anedot/worldpay-cnp"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSale(t *testing.T) {
+	s := NewServer()
+	defer s.Close()
+
+	c := s.Client()
+
+	t.Run("approved", func(t *testing.T) {
+		res, err := c.Sale(context.Background(), "100", &worldpay.Sale{
+			Id:          "1",
+			ReportGroup: "ABC Division",
+			OrderId:     "5234234",
+			Amount:      40000,
+			OrderSource: "ecommerce",
+			Card:        worldpay.Card{Type: "VI", Number: "4005550000081000", ExpDate: "1210"},
+		})
+		assert.Nil(t, err)
+		assert.Equal(t, "11.4", res.Version)
+		assert.Equal(t, "1", res.SaleResponse.Id)
+		assert.Equal(t, "ABC Division", res.SaleResponse.ReportGroup)
+		assert.Equal(t, "5234234", res.SaleResponse.OrderId)
+		assert.Equal(t, "000", res.SaleResponse.Response)
+		assert.Equal(t, "Approved", res.SaleResponse.Message)
+		assert.NotEmpty(t, res.SaleResponse.LitleTxnId)
+	})
+
+	t.Run("with AVS", func(t *testing.T) {
+		res, err := c.Sale(context.Background(), "100", &worldpay.Sale{
+			Id:          "1",
+			OrderId:     "5234234",
+			Amount:      40000,
+			OrderSource: "ecommerce",
+			Card:        worldpay.Card{Type: "VI", Number: "4200410886320101", ExpDate: "1210"},
+		})
+		assert.Nil(t, err)
+		assert.Equal(t, "101", res.SaleResponse.Response)
+		assert.Equal(t, "Issuer Unavailable", res.SaleResponse.Message)
+		assert.Equal(t, "10", res.SaleResponse.FraudResult.AvsResult)
+		assert.True(t, res.SaleResponse.Retryable())
+	})
+
+	t.Run("with Account Updater", func(t *testing.T) {
+		res, _ := c.Sale(context.Background(), "100", &worldpay.Sale{
+			Id:          "1",
+			OrderId:     "5234234",
+			Amount:      40000,
+			OrderSource: "ecommerce",
+			Card:        worldpay.Card{Type: "VI", Number: "4100117890123000", ExpDate: "1210"},
+		})
+		assert.Equal(t, "4100117890123000", res.SaleResponse.AccountUpdater.OriginalCardInfo.Number)
+		assert.Equal(t, "1210", res.SaleResponse.AccountUpdater.NewCardInfo.ExpDate)
+	})
+
+	t.Run("with validation error", func(t *testing.T) {
+		res, err := c.Sale(context.Background(), "100", &worldpay.Sale{
+			Id:          "1",
+			OrderId:     "5234234",
+			Amount:      40000,
+			OrderSource: "ecommerce",
+			Card:        worldpay.Card{Type: "FOO", Number: "4005550000081019", ExpDate: "1210"},
+		})
+		assert.IsType(t, &worldpay.SchemaError{}, err)
+		assert.True(t, res.HasError())
+		assert.Nil(t, res.SaleResponse)
+	})
+}
+
+func TestTransactionTypes(t *testing.T) {
+	s := NewServer()
+	defer s.Close()
+
+	c := s.Client()
+	ctx := context.Background()
+
+	payloads := []interface{}{
+		&worldpay.Authorization{Id: "1", OrderId: "1", Amount: 100, OrderSource: "ecommerce", Card: worldpay.Card{Type: "VI", Number: "4000000000000002", ExpDate: "1210"}},
+		&worldpay.AuthReversal{Id: "1", LitleTxnId: "82924701437133501"},
+		&worldpay.CancelSubscription{SubscriptionId: "12345"},
+		&worldpay.Capture{Id: "1", LitleTxnId: "82924701437133501", Amount: 100},
+		&worldpay.CaptureGivenAuth{Id: "1", OrderId: "1", Amount: 100, OrderSource: "ecommerce", AuthInformation: worldpay.AuthInformation{AuthDate: "2024-01-01", AuthCode: "543216"}, Card: worldpay.Card{Type: "VI", Number: "4005550000081019", ExpDate: "1210"}},
+		&worldpay.CreatePlan{PlanCode: "MONTHLY_DONOR", Name: "Monthly", IntervalType: "MONTHLY", Amount: 100},
+		&worldpay.Credit{Id: "1", LitleTxnId: "82924701437133501"},
+		&worldpay.EcheckCredit{Id: "1", LitleTxnId: "82924701437133501", Amount: 100},
+		&worldpay.EcheckSale{Id: "1", OrderId: "1", Amount: 100, OrderSource: "telephone", Echeck: worldpay.Echeck{AccType: "Checking", AccNum: "5186005800001012", RoutingNum: "000010101"}},
+		&worldpay.EcheckVoid{Id: "1", LitleTxnId: "82924701437133501"},
+		&worldpay.ForceCapture{Id: "1", OrderId: "1", Amount: 100, OrderSource: "ecommerce", Card: worldpay.Card{Type: "VI", Number: "4005550000081019", ExpDate: "1210"}},
+		&worldpay.RegisterTokenRequest{Id: "1", OrderId: "1", AccountNumber: "4457119922390123"},
+		&worldpay.UpdatePlan{PlanCode: "MONTHLY_DONOR"},
+		&worldpay.UpdateSubscription{SubscriptionId: "12345"},
+		&worldpay.Void{Id: "1", LitleTxnId: "82924701437133501"},
+	}
+
+	for _, payload := range payloads {
+		req, _ := c.NewRequest(ctx, "100", payload)
+
+		var res worldpay.LitleOnlineResponse
+		err := c.Send(req, &res)
+		assert.Nil(t, err, "%T", payload)
+		assert.NotNil(t, res.TransactionResponse(), "%T", payload)
+		assert.True(t, res.TransactionResponse().Approved(), "%T", payload)
+	}
+
+	assert.Len(t, s.Requests(), len(payloads))
+}
+
+func TestRegisterToken(t *testing.T) {
+	s := NewServer()
+	defer s.Close()
+
+	c := s.Client()
+
+	res, _ := c.RegisterToken(context.Background(), "100", &worldpay.RegisterTokenRequest{Id: "1", OrderId: "1", AccountNumber: "4457119922390123"})
+	assert.Equal(t, "801", res.RegisterTokenResponse.Response)
+	assert.Equal(t, "445711", res.RegisterTokenResponse.Bin)
+	assert.Equal(t, "VI", res.RegisterTokenResponse.Type)
+	token := res.RegisterTokenResponse.LitleToken
+
+	res, _ = c.RegisterToken(context.Background(), "100", &worldpay.RegisterTokenRequest{Id: "2", OrderId: "2", AccountNumber: "4457119922390123"})
+	assert.Equal(t, "802", res.RegisterTokenResponse.Response)
+	assert.Equal(t, token, res.RegisterTokenResponse.LitleToken)
+}
+
+func TestQueryTransaction(t *testing.T) {
+	s := NewServer()
+	defer s.Close()
+
+	c := s.Client()
+	ctx := context.Background()
+
+	res, _ := c.QueryTransaction(ctx, "100", &worldpay.QueryTransaction{Id: "2", OrigId: "1", OrigActionType: "S"})
+	assert.Equal(t, "151", res.QueryTransactionResponse.Response)
+
+	sale, _ := c.Sale(ctx, "100", &worldpay.Sale{Id: "1", OrderId: "1", Amount: 100, OrderSource: "ecommerce", Card: worldpay.Card{Type: "VI", Number: "4005550000081000", ExpDate: "1210"}})
+
+	res, _ = c.QueryTransaction(ctx, "100", &worldpay.QueryTransaction{Id: "2", OrigId: "1", OrigActionType: "S"})
+	assert.Equal(t, "150", res.QueryTransactionResponse.Response)
+	assert.Equal(t, 1, res.QueryTransactionResponse.MatchCount)
+	assert.Equal(t, sale.SaleResponse.LitleTxnId, res.QueryTransactionResponse.Results.SaleResponses[0].LitleTxnId)
+}
+
+func TestHandle(t *testing.T) {
+	s := NewServer()
+	defer s.Close()
+
+	s.Handle(func(req *worldpay.LitleOnlineRequest) *worldpay.LitleOnlineResponse {
+		if req.Void == nil {
+			return nil
+		}
+		return &worldpay.LitleOnlineResponse{
+			Response: "0",
+			Message:  "Valid Format",
+			VoidResponse: &worldpay.VoidResponse{
+				Id:       req.Void.Id,
+				Response: "362",
+				Message:  "Transaction Not Voided - Already Settled",
+			},
+		}
+	})
+
+	c := s.Client()
+
+	res, _ := c.Void(context.Background(), "100", &worldpay.Void{Id: "1", LitleTxnId: "82924701437133501"})
+	assert.Equal(t, "362", res.VoidResponse.Response)
+	assert.Equal(t, "11.4", res.Version)
+
+	credit, _ := c.Credit(context.Background(), "100", &worldpay.Credit{Id: "1", LitleTxnId: "82924701437133501"})
+	assert.Equal(t, "000", credit.CreditResponse.Response)
+}
+
+func TestSchemaVersion12(t *testing.T) {
+	s := NewServer()
+	defer s.Close()
+
+	var body string
+	s.Handle(func(req *worldpay.LitleOnlineRequest) *worldpay.LitleOnlineResponse {
+		assert.Equal(t, "12.0", req.Version)
+		return nil
+	})
+	c := s.Client(worldpay.WithSchemaVersion(worldpay.SchemaVersion12), worldpay.WithLogger(writerFunc(func(p []byte) {
+		body += string(p)
+	})))
+
+	res, err := c.Sale(context.Background(), "100", &worldpay.Sale{
+		Id:          "1",
+		OrderId:     "5234234",
+		Amount:      40000,
+		OrderSource: "ecommerce",
+		Card:        worldpay.Card{Type: "VI", Number: "4005550000081000", ExpDate: "1210"},
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, "12.0", res.Version)
+	assert.Equal(t, "000", res.SaleResponse.Response)
+	assert.NotEmpty(t, res.SaleResponse.LitleTxnId)
+
+	// The response is written in the request's schema version.
+	assert.Contains(t, body, "<cnpOnlineResponse")
+	assert.Contains(t, body, "http://www.vantivcnp.com/schema")
+	assert.Contains(t, body, "<cnpTxnId>")
+	assert.NotContains(t, body, "litleTxnId")
+
+	void, err := c.Void(context.Background(), "100", &worldpay.Void{Id: "2", LitleTxnId: res.SaleResponse.LitleTxnId})
+	assert.Nil(t, err)
+	assert.Equal(t, "000", void.VoidResponse.Response)
+}
+
+func TestHandleRequests(t *testing.T) {
+	s := NewServer()
+	defer s.Close()
+
+	// A responder may call back into the server.
+	s.Handle(func(req *worldpay.LitleOnlineRequest) *worldpay.LitleOnlineResponse {
+		if req.Void != nil && len(s.Requests()) == 0 {
+			return &worldpay.LitleOnlineResponse{
+				Response: "0",
+				Message:  "Valid Format",
+				VoidResponse: &worldpay.VoidResponse{
+					Id:       req.Void.Id,
+					Response: "360",
+				},
+			}
+		}
+		return nil
+	})
+
+	c := s.Client()
+
+	res, err := c.Void(context.Background(), "100", &worldpay.Void{Id: "1", LitleTxnId: "82924701437133501"})
+	assert.Nil(t, err)
+	assert.Equal(t, "360", res.VoidResponse.Response)
+	assert.Len(t, s.Requests(), 1)
+}
+
+type writerFunc func(p []byte)
+
+func (f writerFunc) Write(p []byte) (int, error) {
+	f(p)
+	return len(p), nil
+}
@@ -0,0 +1,252 @@
+package worldpaytest
+
+import "github.com/anedot/worldpay-cnp"
+
+// Lifecycle response codes returned by the simulator.
+const (
+	codeApproved          = "000"
+	codeDepleted          = "111"
+	codeNoNeedToReverse   = "306"
+	codeReversalMismatch  = "336"
+	codeNotFound          = "360"
+	codeAuthNotAvailable  = "361"
+	codeAlreadySettled    = "362"
+	codeCreditExceedsSale = "365"
+)
+
+type (
+	// Transaction is the simulator's view of an approved transaction.
+	Transaction struct {
+		LitleTxnId string
+		// Type is the transaction's element name, e.g. "authorization".
+		Type   string
+		Amount int
+		// Captured is the amount captured against an authorization.
+		Captured int
+		// Reversed is the amount of an authorization that has been reversed.
+		Reversed int
+		// Credited is the amount refunded against a sale or capture.
+		Credited int
+		Voided   bool
+		Settled  bool
+
+		// parent is the litleTxnId of the authorization a capture, or the
+		// deposit a credit, was made against.
+		parent string
+	}
+
+	// ledger tracks approved transactions by litleTxnId.
+	ledger struct {
+		transactions map[string]*Transaction
+	}
+)
+
+// NewSimulator starts a fake server that, on top of the behaviour of
+// NewServer, tracks the lifecycle of each approved transaction. Captures,
+// reversals, credits and voids are checked against the transaction they
+// refer to, and fail with the gateway's response codes when they are not
+// allowed, e.g. 360 for an unknown litleTxnId, 362 for voiding a settled
+// transaction or 365 for refunding more than was captured.
+func NewSimulator() *Server {
+	s := NewServer()
+	s.ledger = &ledger{transactions: make(map[string]*Transaction)}
+	return s
+}
+
+// Transaction returns the tracked state of an approved transaction.
+func (s *Server) Transaction(litleTxnId string) (Transaction, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.ledger == nil {
+		return Transaction{}, false
+	}
+	t, ok := s.ledger.transactions[litleTxnId]
+	if !ok {
+		return Transaction{}, false
+	}
+	return *t, true
+}
+
+// Settle marks every deposit and credit as settled, as the nightly
+// settlement would, after which they can no longer be voided.
+func (s *Server) Settle() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.ledger == nil {
+		return
+	}
+	for _, t := range s.ledger.transactions {
+		if t.Type != "authorization" && !t.Voided {
+			t.Settled = true
+		}
+	}
+}
+
+// check returns the response code for a transaction that follows up on an
+// earlier one.
+func (l *ledger) check(payload interface{}) string {
+	switch p := payload.(type) {
+	case *worldpay.AuthReversal:
+		auth := l.find(p.LitleTxnId, "authorization")
+		switch {
+		case auth == nil:
+			return codeNotFound
+		case auth.Voided || (auth.remaining() == 0 && auth.Reversed > 0):
+			return codeNoNeedToReverse
+		case auth.remaining() == 0:
+			return codeDepleted
+		case p.Amount != nil && *p.Amount > auth.remaining():
+			return codeReversalMismatch
+		}
+	case *worldpay.Capture:
+		auth := l.find(p.LitleTxnId, "authorization")
+		switch {
+		case auth == nil:
+			return codeNotFound
+		case auth.Voided || (auth.Reversed > 0 && auth.remaining() == 0):
+			return codeAuthNotAvailable
+		case auth.remaining() == 0 || p.Amount > auth.remaining():
+			return codeDepleted
+		}
+	case *worldpay.Credit:
+		if p.LitleTxnId == "" {
+			return codeApproved
+		}
+		deposit := l.find(p.LitleTxnId, "sale", "capture", "captureGivenAuth", "forceCapture")
+		switch {
+		case deposit == nil || deposit.Voided:
+			return codeNotFound
+		case p.Amount == nil && deposit.Credited > 0,
+			p.Amount != nil && *p.Amount > deposit.Amount-deposit.Credited:
+			return codeCreditExceedsSale
+		}
+	case *worldpay.EcheckCredit:
+		deposit := l.find(p.LitleTxnId, "echeckSale")
+		switch {
+		case deposit == nil || deposit.Voided:
+			return codeNotFound
+		case p.Amount > deposit.Amount-deposit.Credited:
+			return codeCreditExceedsSale
+		}
+	case *worldpay.EcheckVoid:
+		return l.checkVoid(l.find(p.LitleTxnId, "echeckSale", "echeckCredit"))
+	case *worldpay.Void:
+		return l.checkVoid(l.find(p.LitleTxnId, "sale", "capture", "captureGivenAuth", "forceCapture", "credit"))
+	}
+	return codeApproved
+}
+
+func (l *ledger) checkVoid(t *Transaction) string {
+	switch {
+	case t == nil || t.Voided:
+		return codeNotFound
+	case t.Settled:
+		return codeAlreadySettled
+	}
+	return codeApproved
+}
+
+// record updates the ledger with an approved transaction.
+func (l *ledger) record(payload interface{}, res *worldpay.LitleOnlineResponse) {
+	txn := res.TransactionResponse()
+	if txn == nil || !txn.Approved() {
+		return
+	}
+
+	switch p := payload.(type) {
+	case *worldpay.Authorization:
+		l.add(res.AuthorizationResponse.LitleTxnId, "authorization", p.Amount, "")
+	case *worldpay.AuthReversal:
+		auth := l.transactions[p.LitleTxnId]
+		if p.Amount != nil {
+			auth.Reversed += *p.Amount
+		} else {
+			auth.Reversed += auth.remaining()
+		}
+	case *worldpay.Capture:
+		auth := l.transactions[p.LitleTxnId]
+		amount := p.Amount
+		if amount == 0 {
+			amount = auth.remaining()
+		}
+		auth.Captured += amount
+		l.add(res.CaptureResponse.LitleTxnId, "capture", amount, p.LitleTxnId)
+	case *worldpay.CaptureGivenAuth:
+		l.add(res.CaptureGivenAuthResponse.LitleTxnId, "captureGivenAuth", p.Amount, "")
+	case *worldpay.Credit:
+		amount := 0
+		if p.LitleTxnId != "" {
+			deposit := l.transactions[p.LitleTxnId]
+			amount = deposit.Amount - deposit.Credited
+			if p.Amount != nil {
+				amount = *p.Amount
+			}
+			deposit.Credited += amount
+		} else if p.Amount != nil {
+			amount = *p.Amount
+		}
+		l.add(res.CreditResponse.LitleTxnId, "credit", amount, p.LitleTxnId)
+	case *worldpay.EcheckCredit:
+		l.transactions[p.LitleTxnId].Credited += p.Amount
+		l.add(res.EcheckCreditResponse.LitleTxnId, "echeckCredit", p.Amount, p.LitleTxnId)
+	case *worldpay.EcheckSale:
+		l.add(res.EcheckSaleResponse.LitleTxnId, "echeckSale", p.Amount, "")
+	case *worldpay.EcheckVoid:
+		l.void(p.LitleTxnId)
+	case *worldpay.ForceCapture:
+		l.add(res.ForceCaptureResponse.LitleTxnId, "forceCapture", p.Amount, "")
+	case *worldpay.Sale:
+		l.add(res.SaleResponse.LitleTxnId, "sale", p.Amount, "")
+	case *worldpay.Void:
+		l.void(p.LitleTxnId)
+	}
+}
+
+func (l *ledger) add(litleTxnId, typ string, amount int, parent string) {
+	l.transactions[litleTxnId] = &Transaction{
+		LitleTxnId: litleTxnId,
+		Type:       typ,
+		Amount:     amount,
+		parent:     parent,
+	}
+}
+
+// void cancels a transaction, releasing what it took from its parent.
+func (l *ledger) void(litleTxnId string) {
+	t := l.transactions[litleTxnId]
+	t.Voided = true
+
+	parent, ok := l.transactions[t.parent]
+	if !ok {
+		return
+	}
+	switch t.Type {
+	case "capture":
+		parent.Captured -= t.Amount
+	case "credit", "echeckCredit":
+		parent.Credited -= t.Amount
+	}
+}
+
+// find returns the transaction with the given litleTxnId if it is one of the
+// given types.
+func (l *ledger) find(litleTxnId string, types ...string) *Transaction {
+	t, ok := l.transactions[litleTxnId]
+	if !ok {
+		return nil
+	}
+	for _, typ := range types {
+		if t.Type == typ {
+			return t
+		}
+	}
+	return nil
+}
+
+// remaining is the amount of an authorization still available to capture or
+// reverse.
+func (t *Transaction) remaining() int {
+	return t.Amount - t.Captured - t.Reversed
+}
@@ -0,0 +1,144 @@
+package worldpaytest
+
+import (
+	"context"
+	"testing"
+
+	"github.com/anedot/worldpay-cnp"
+	"github.com/stretchr/testify/assert"
+)
+
+var visa = worldpay.Card{Type: "VI", Number: "4005550000081000", ExpDate: "1210"}
+
+func TestSimulatorCapture(t *testing.T) {
+	s := NewSimulator()
+	defer s.Close()
+
+	c := s.Client()
+	ctx := context.Background()
+
+	res, _ := c.Capture(ctx, "100", &worldpay.Capture{Id: "1", LitleTxnId: "12345", Amount: 100})
+	assert.Equal(t, "360", res.CaptureResponse.Response)
+
+	auth, _ := c.Authorization(ctx, "100", &worldpay.Authorization{Id: "1", OrderId: "1", Amount: 1000, OrderSource: "ecommerce", Card: visa})
+	authId := auth.AuthorizationResponse.LitleTxnId
+
+	res, _ = c.Capture(ctx, "100", &worldpay.Capture{Id: "2", LitleTxnId: authId, Amount: 600})
+	assert.Equal(t, "000", res.CaptureResponse.Response)
+
+	res, _ = c.Capture(ctx, "100", &worldpay.Capture{Id: "3", LitleTxnId: authId, Amount: 600})
+	assert.Equal(t, "111", res.CaptureResponse.Response)
+
+	reversal, _ := c.AuthReversal(ctx, "100", &worldpay.AuthReversal{Id: "4", LitleTxnId: authId})
+	assert.Equal(t, "000", reversal.AuthReversalResponse.Response)
+
+	reversal, _ = c.AuthReversal(ctx, "100", &worldpay.AuthReversal{Id: "5", LitleTxnId: authId})
+	assert.Equal(t, "306", reversal.AuthReversalResponse.Response)
+
+	res, _ = c.Capture(ctx, "100", &worldpay.Capture{Id: "6", LitleTxnId: authId, Amount: 100})
+	assert.Equal(t, "361", res.CaptureResponse.Response)
+
+	state, ok := s.Transaction(authId)
+	assert.True(t, ok)
+	assert.Equal(t, 1000, state.Amount)
+	assert.Equal(t, 600, state.Captured)
+	assert.Equal(t, 400, state.Reversed)
+}
+
+func TestSimulatorDeclinedAuthorization(t *testing.T) {
+	s := NewSimulator()
+	defer s.Close()
+
+	c := s.Client()
+	ctx := context.Background()
+
+	auth, _ := c.Authorization(ctx, "100", &worldpay.Authorization{Id: "1", OrderId: "1", Amount: 1000, OrderSource: "ecommerce", Card: worldpay.Card{Type: "VI", Number: "4457010100000008", ExpDate: "1210"}})
+	assert.Equal(t, "110", auth.AuthorizationResponse.Response)
+
+	res, _ := c.Capture(ctx, "100", &worldpay.Capture{Id: "2", LitleTxnId: auth.AuthorizationResponse.LitleTxnId, Amount: 1000})
+	assert.Equal(t, "360", res.CaptureResponse.Response)
+}
+
+func TestSimulatorCredit(t *testing.T) {
+	s := NewSimulator()
+	defer s.Close()
+
+	c := s.Client()
+	ctx := context.Background()
+
+	sale, _ := c.Sale(ctx, "100", &worldpay.Sale{Id: "1", OrderId: "1", Amount: 1000, OrderSource: "ecommerce", Card: visa})
+	saleId := sale.SaleResponse.LitleTxnId
+
+	amount := 700
+	res, _ := c.Credit(ctx, "100", &worldpay.Credit{Id: "2", LitleTxnId: saleId, Amount: &amount})
+	assert.Equal(t, "000", res.CreditResponse.Response)
+
+	res, _ = c.Credit(ctx, "100", &worldpay.Credit{Id: "3", LitleTxnId: saleId, Amount: &amount})
+	assert.Equal(t, "365", res.CreditResponse.Response)
+
+	res, _ = c.Credit(ctx, "100", &worldpay.Credit{Id: "4", LitleTxnId: "12345", Amount: &amount})
+	assert.Equal(t, "360", res.CreditResponse.Response)
+
+	state, _ := s.Transaction(saleId)
+	assert.Equal(t, 700, state.Credited)
+}
+
+func TestSimulatorVoid(t *testing.T) {
+	s := NewSimulator()
+	defer s.Close()
+
+	c := s.Client()
+	ctx := context.Background()
+
+	sale, _ := c.Sale(ctx, "100", &worldpay.Sale{Id: "1", OrderId: "1", Amount: 1000, OrderSource: "ecommerce", Card: visa})
+	saleId := sale.SaleResponse.LitleTxnId
+
+	res, _ := c.Void(ctx, "100", &worldpay.Void{Id: "2", LitleTxnId: saleId})
+	assert.Equal(t, "000", res.VoidResponse.Response)
+
+	res, _ = c.Void(ctx, "100", &worldpay.Void{Id: "3", LitleTxnId: saleId})
+	assert.Equal(t, "360", res.VoidResponse.Response)
+
+	sale, _ = c.Sale(ctx, "100", &worldpay.Sale{Id: "4", OrderId: "2", Amount: 1000, OrderSource: "ecommerce", Card: visa})
+	s.Settle()
+
+	res, _ = c.Void(ctx, "100", &worldpay.Void{Id: "5", LitleTxnId: sale.SaleResponse.LitleTxnId})
+	assert.Equal(t, "362", res.VoidResponse.Response)
+}
+
+func TestSimulatorVoidCapture(t *testing.T) {
+	s := NewSimulator()
+	defer s.Close()
+
+	c := s.Client()
+	ctx := context.Background()
+
+	auth, _ := c.Authorization(ctx, "100", &worldpay.Authorization{Id: "1", OrderId: "1", Amount: 1000, OrderSource: "ecommerce", Card: visa})
+	authId := auth.AuthorizationResponse.LitleTxnId
+
+	capture, _ := c.Capture(ctx, "100", &worldpay.Capture{Id: "2", LitleTxnId: authId, Amount: 1000})
+	c.Void(ctx, "100", &worldpay.Void{Id: "3", LitleTxnId: capture.CaptureResponse.LitleTxnId})
+
+	res, _ := c.Capture(ctx, "100", &worldpay.Capture{Id: "4", LitleTxnId: authId, Amount: 1000})
+	assert.Equal(t, "000", res.CaptureResponse.Response)
+}
+
+func TestSimulatorEcheck(t *testing.T) {
+	s := NewSimulator()
+	defer s.Close()
+
+	c := s.Client()
+	ctx := context.Background()
+
+	sale, _ := c.EcheckSale(ctx, "100", &worldpay.EcheckSale{Id: "1", OrderId: "1", Amount: 1000, OrderSource: "telephone", Echeck: worldpay.Echeck{AccType: "Checking", AccNum: "5186005800001012", RoutingNum: "000010101"}})
+	saleId := sale.EcheckSaleResponse.LitleTxnId
+
+	res, _ := c.EcheckCredit(ctx, "100", &worldpay.EcheckCredit{Id: "2", LitleTxnId: saleId, Amount: 1001})
+	assert.Equal(t, "365", res.EcheckCreditResponse.Response)
+
+	void, _ := c.EcheckVoid(ctx, "100", &worldpay.EcheckVoid{Id: "3", LitleTxnId: saleId})
+	assert.Equal(t, "000", void.EcheckVoidResponse.Response)
+
+	res, _ = c.EcheckCredit(ctx, "100", &worldpay.EcheckCredit{Id: "4", LitleTxnId: saleId, Amount: 100})
+	assert.Equal(t, "360", res.EcheckCreditResponse.Response)
+}